@@ -0,0 +1,265 @@
+package backoffd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+type Client struct {
+	mu     sync.Mutex
+	path   string
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// Dial ...
+// Возвращает клиента демона, слушающего Unix-сокет path
+func Dial(path string) (*Client, error) {
+
+	c := &Client{path: path}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close ...
+func (c *Client) Close() error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn = nil
+
+	return err
+}
+
+// Delay ...
+// Возвращает задержку с именем name, хранящуюся в демоне
+func (c *Client) Delay(name string) *RemoteDelay {
+	return &RemoteDelay{client: c, name: name}
+}
+
+// Do ...
+// Выполняет запрос через общее соединение.
+// При ошибке соединение будет открыто заново при следующем запросе
+func (c *Client) Do(req Request) (Response, error) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return Response{}, err
+		}
+	}
+
+	resp, err := roundTrip(c.conn, c.reader, req)
+	if err != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+
+	return resp, err
+}
+
+// wait выполняет wait в отдельном соединении,
+// которое закрывается при отмене контекста
+func (c *Client) wait(ctx context.Context, name string) (Response, error) {
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "unix", c.path)
+	if err != nil {
+		return Response{}, err
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	resp, err := roundTrip(conn, bufio.NewReader(conn), Request{Op: OpWait, Name: name})
+	if ctx.Err() != nil {
+		return Response{}, ctx.Err()
+	}
+
+	return resp, err
+}
+
+func (c *Client) connect() error {
+
+	conn, err := net.Dial("unix", c.path)
+	if err != nil {
+		return err
+	}
+
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+
+	return nil
+}
+
+func roundTrip(conn net.Conn, r *bufio.Reader, req Request) (Response, error) {
+
+	var resp Response
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return resp, err
+	}
+
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return resp, err
+	}
+
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+
+	return resp, nil
+}
+
+// RemoteDelay ...
+// Задержка, хранящаяся в демоне. Методы повторяют exponentialbackoff.Delay,
+// ошибка последнего запроса доступна через Err
+type RemoteDelay struct {
+	client *Client
+	name   string
+
+	mu   sync.Mutex
+	last Response
+	err  error
+}
+
+// Incr ...
+// Увеличение задержки
+func (d *RemoteDelay) Incr() *RemoteDelay {
+	return d.do(Request{Op: OpIncr})
+}
+
+// Decr ...
+// Уменьшение задержки
+func (d *RemoteDelay) Decr() *RemoteDelay {
+	return d.do(Request{Op: OpDecr})
+}
+
+// Reset ...
+func (d *RemoteDelay) Reset() *RemoteDelay {
+	return d.do(Request{Op: OpReset})
+}
+
+// GetDelay ...
+func (d *RemoteDelay) GetDelay() int {
+
+	d.do(Request{Op: OpGet})
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.last.Delay
+}
+
+// SetDelay ...
+// Установить значение задежки
+func (d *RemoteDelay) SetDelay(v int) *RemoteDelay {
+	return d.do(Request{Op: OpSet, Value: v})
+}
+
+// SetDurationUnits ...
+// Установить единицу времени, в которой будет измеряться задержка
+func (d *RemoteDelay) SetDurationUnits(du time.Duration) *RemoteDelay {
+	return d.do(Request{Op: OpUnits, Units: du})
+}
+
+// Duration ...
+// Текущая задержка в единицах времени
+func (d *RemoteDelay) Duration() time.Duration {
+
+	d.do(Request{Op: OpGet})
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.last.Duration
+}
+
+// IssetDelay ...
+// Установлена ли задержка
+func (d *RemoteDelay) IssetDelay() bool {
+	return d.GetDelay() > 0
+}
+
+// Backoff ...
+// Выполнить задержку, если возможно
+//
+// Возвращает:
+//
+//	bool - была ли задержка
+//	error - ошибка, если задержка была прервана
+//	time.Duration - фактическое время задержки
+func (d *RemoteDelay) Backoff(ctx context.Context) (bool, error, time.Duration) {
+
+	ts := time.Now()
+
+	resp, err := d.client.wait(ctx, d.name)
+	d.store(resp, err)
+
+	return resp.Waited, err, time.Since(ts)
+}
+
+// Err ...
+// Ошибка последнего запроса к демону
+func (d *RemoteDelay) Err() error {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.err
+}
+
+func (d *RemoteDelay) do(req Request) *RemoteDelay {
+
+	req.Name = d.name
+	resp, err := d.client.Do(req)
+	d.store(resp, err)
+
+	return d
+}
+
+func (d *RemoteDelay) store(resp Response, err error) {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.err = err
+	if err == nil {
+		d.last = resp
+	}
+}
@@ -0,0 +1,38 @@
+// Shared backoff state served over a Unix domain socket
+// Общее состояние задержек, доступное через Unix-сокет
+//
+// Протокол: по одному JSON-объекту на строку в каждую сторону.
+//
+//	-> {"op":"incr","name":"db"}
+//	<- {"delay":2,"duration":2000000000}
+//
+// Операции: get, incr, decr, reset, set (поле value), units (поле units), wait.
+// Операция wait отвечает после истечения текущей задержки.
+
+package backoffd
+
+import "time"
+
+const (
+	OpGet   = "get"
+	OpIncr  = "incr"
+	OpDecr  = "decr"
+	OpReset = "reset"
+	OpSet   = "set"
+	OpUnits = "units"
+	OpWait  = "wait"
+)
+
+type Request struct {
+	Op    string        `json:"op"`
+	Name  string        `json:"name"`
+	Value int           `json:"value,omitempty"` // Значение задержки для set
+	Units time.Duration `json:"units,omitempty"` // Единица времени задержки для units
+}
+
+type Response struct {
+	Delay    int           `json:"delay"`            // Текущее значение задержки
+	Duration time.Duration `json:"duration"`         // Текущая задержка в наносекундах
+	Waited   bool          `json:"waited,omitempty"` // Была ли задержка (wait)
+	Error    string        `json:"error,omitempty"`
+}
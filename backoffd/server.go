@@ -0,0 +1,180 @@
+package backoffd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+type Server struct {
+	mu       sync.Mutex
+	delays   map[string]*exponentialbackoff.Delay
+	newDelay func(name string) (*exponentialbackoff.Delay, error)
+}
+
+// NewServer ...
+// Возвращает сервер, создающий задержку при первом
+// обращении к имени с помощью newDelay
+func NewServer(newDelay func(name string) (*exponentialbackoff.Delay, error)) *Server {
+	return &Server{
+		delays:   make(map[string]*exponentialbackoff.Delay),
+		newDelay: newDelay,
+	}
+}
+
+// Serve ...
+// Принимает соединения до отмены контекста или ошибки Accept
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		go s.ServeConn(ctx, conn)
+	}
+}
+
+// ServeConn ...
+// Обрабатывает запросы одного соединения. Если клиент закрыл
+// соединение, незавершённый wait прерывается
+func (s *Server) ServeConn(ctx context.Context, conn net.Conn) {
+
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines := make(chan []byte)
+
+	go func() {
+
+		defer cancel()
+		defer close(lines)
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			select {
+			case lines <- append([]byte(nil), scanner.Bytes()...):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	enc := json.NewEncoder(conn)
+
+	for line := range lines {
+
+		var req Request
+
+		resp := Response{}
+		if err := json.Unmarshal(line, &req); err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp = s.handle(ctx, req)
+		}
+
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handle(ctx context.Context, req Request) Response {
+
+	d, err := s.delay(req.Name)
+	if err != nil {
+		return Response{Error: err.Error()}
+	}
+
+	var resp Response
+
+	if req.Op == OpWait {
+
+		s.mu.Lock()
+		wait := d.Duration()
+		s.mu.Unlock()
+
+		if wait > 0 {
+
+			t := time.NewTimer(wait)
+
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+				return Response{Error: ctx.Err().Error()}
+			}
+
+			resp.Waited = true
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch req.Op {
+	case OpGet, OpWait:
+	case OpIncr:
+		d.Incr()
+	case OpDecr:
+		d.Decr()
+	case OpReset:
+		d.Reset()
+	case OpSet:
+		d.SetDelay(req.Value)
+	case OpUnits:
+		if req.Units <= 0 {
+			return Response{Error: fmt.Sprintf("units %s is not positive", req.Units)}
+		}
+		d.SetDurationUnits(req.Units)
+	default:
+		return Response{Error: fmt.Sprintf("unknown op %q", req.Op)}
+	}
+
+	resp.Delay = d.GetDelay()
+	resp.Duration = d.Duration()
+
+	return resp
+}
+
+func (s *Server) delay(name string) (*exponentialbackoff.Delay, error) {
+
+	if name == "" {
+		return nil, errors.New("empty name")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.delays[name]; ok {
+		return d, nil
+	}
+
+	d, err := s.newDelay(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.delays[name] = d
+
+	return d, nil
+}
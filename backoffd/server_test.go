@@ -0,0 +1,73 @@
+package backoffd
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+func newTestServer() *Server {
+	return NewServer(func(string) (*exponentialbackoff.Delay, error) {
+		return exponentialbackoff.New(&exponentialbackoff.Config{Max: 60, Factor: 2}), nil
+	})
+}
+
+func TestWaitStopsOnDisconnect(t *testing.T) {
+
+	s := newTestServer()
+	s.handle(context.Background(), Request{Op: OpIncr, Name: "db"})
+
+	server, client := net.Pipe()
+	done := make(chan struct{})
+
+	go func() {
+		s.ServeConn(context.Background(), server)
+		close(done)
+	}()
+
+	if _, err := client.Write([]byte(`{"op":"wait","name":"db"}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	client.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ServeConn still waiting after the client disconnected")
+	}
+}
+
+func TestRemoteDelaySetDurationUnits(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "backoffd.sock")
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go newTestServer().Serve(ctx, l)
+
+	c, err := Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	d := c.Delay("db")
+
+	if du := d.SetDurationUnits(time.Millisecond).Incr().Duration(); du != 2*time.Millisecond {
+		t.Fatalf("Duration: got %s, want 2ms (err %v)", du, d.Err())
+	}
+
+	if d.SetDurationUnits(0); d.Err() == nil {
+		t.Fatal("SetDurationUnits(0): want error")
+	}
+}
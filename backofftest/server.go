@@ -0,0 +1,227 @@
+// Fault-injecting HTTP server for testing retrying clients
+// Тестовый HTTP-сервер с заданным сценарием отказов
+
+package backofftest
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Action ...
+// Ответ сервера на один запрос
+type Action func(w http.ResponseWriter, r *http.Request, closing <-chan struct{})
+
+// Script ...
+// Сценарий: возвращает действие для запроса с номером n (с нуля)
+type Script func(n int) Action
+
+// Request ...
+// Запись журнала запросов
+type Request struct {
+	Time   time.Time
+	Method string
+	URL    string
+	Header http.Header
+}
+
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	script   Script
+	requests []Request
+	closing  chan struct{}
+	once     sync.Once
+}
+
+// NewServer ...
+// Запускает сервер, отвечающий по сценарию script
+func NewServer(script Script) *Server {
+
+	s := &Server{
+		script:  script,
+		closing: make(chan struct{}),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// SetScript ...
+// Заменяет сценарий и сбрасывает нумерацию запросов
+func (s *Server) SetScript(script Script) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.script = script
+	s.requests = nil
+}
+
+// Requests ...
+// Журнал запросов в порядке поступления
+func (s *Server) Requests() []Request {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// Intervals ...
+// Интервалы между последовательными запросами,
+// то есть наблюдаемые задержки клиента
+func (s *Server) Intervals() []time.Duration {
+
+	requests := s.Requests()
+	if len(requests) < 2 {
+		return nil
+	}
+
+	intervals := make([]time.Duration, 0, len(requests)-1)
+	for i := 1; i < len(requests); i++ {
+		intervals = append(intervals, requests[i].Time.Sub(requests[i-1].Time))
+	}
+
+	return intervals
+}
+
+// Close ...
+// Прерывает зависшие запросы и останавливает сервер
+func (s *Server) Close() {
+	s.once.Do(func() {
+		close(s.closing)
+	})
+	s.Server.Close()
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+
+	s.mu.Lock()
+	n := len(s.requests)
+	s.requests = append(s.requests, Request{
+		Time:   time.Now(),
+		Method: r.Method,
+		URL:    r.URL.String(),
+		Header: r.Header.Clone(),
+	})
+	action := s.script(n)
+	s.mu.Unlock()
+
+	action(w, r, s.closing)
+}
+
+// OK ...
+// Ответ 200 OK
+func OK() Action {
+	return Status(http.StatusOK)
+}
+
+// Status ...
+// Ответ с кодом code
+func Status(code int) Action {
+	return func(w http.ResponseWriter, _ *http.Request, _ <-chan struct{}) {
+		w.WriteHeader(code)
+	}
+}
+
+// RetryAfter ...
+// Ответ 429 Too Many Requests с заголовком Retry-After в секундах.
+// Дробные секунды округляются вверх
+func RetryAfter(d time.Duration) Action {
+	return func(w http.ResponseWriter, _ *http.Request, _ <-chan struct{}) {
+		w.Header().Set("Retry-After", strconv.Itoa(int((d+time.Second-1)/time.Second)))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+}
+
+// Reset ...
+// Разрыв соединения без ответа (TCP RST)
+func Reset() Action {
+	return func(w http.ResponseWriter, _ *http.Request, _ <-chan struct{}) {
+
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			panic("backofftest: response writer does not support hijacking")
+		}
+
+		conn, _, err := hj.Hijack()
+		if err != nil {
+			return
+		}
+
+		if tcp, ok := conn.(*net.TCPConn); ok {
+			tcp.SetLinger(0)
+		}
+
+		conn.Close()
+	}
+}
+
+// Hang ...
+// Не отвечает до отмены запроса клиентом или остановки сервера
+func Hang() Action {
+	return func(_ http.ResponseWriter, r *http.Request, closing <-chan struct{}) {
+		select {
+		case <-r.Context().Done():
+		case <-closing:
+		}
+	}
+}
+
+// Always ...
+// Сценарий с одинаковым ответом на все запросы
+func Always(a Action) Script {
+	return func(int) Action {
+		return a
+	}
+}
+
+// Sequence ...
+// Сценарий с ответами по порядку, последний ответ повторяется
+func Sequence(actions ...Action) Script {
+	return func(n int) Action {
+
+		if len(actions) == 0 {
+			return OK()
+		}
+
+		if n >= len(actions) {
+			n = len(actions) - 1
+		}
+
+		return actions[n]
+	}
+}
+
+// FailFirst ...
+// Сценарий: первые n запросов получают fail, остальные - 200 OK
+func FailFirst(n int, fail Action) Script {
+	return func(i int) Action {
+
+		if i < n {
+			return fail
+		}
+
+		return OK()
+	}
+}
+
+// Flap ...
+// Сценарий по циклическому шаблону: '.' - 200 OK, любой другой символ - fail.
+// Например "..x" - два успешных ответа, затем отказ
+func Flap(pattern string, fail Action) Script {
+	return func(i int) Action {
+
+		if pattern == "" || pattern[i%len(pattern)] == '.' {
+			return OK()
+		}
+
+		return fail
+	}
+}
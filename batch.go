@@ -0,0 +1,97 @@
+package exponentialbackoff
+
+import (
+	"context"
+	"fmt"
+)
+
+// BatchFunc ...
+// Обработка пакета элементов. Возвращает ошибки по индексам элементов
+// пакета, nil - элемент обработан
+type BatchFunc func(ctx context.Context, items []interface{}) []error
+
+// BatchResult ...
+// Результат BatchRetry
+type BatchResult struct {
+	Errors   []error // Ошибки по индексам исходных элементов, nil - элемент обработан
+	Attempts int     // Количество вызовов функции обработки
+	Err      error   // Ошибка контекста, если повторы были прерваны
+}
+
+// Failed ...
+// Индексы исходных элементов, которые не удалось обработать
+func (r *BatchResult) Failed() []int {
+
+	var failed []int
+
+	for i, err := range r.Errors {
+		if err != nil {
+			failed = append(failed, i)
+		}
+	}
+
+	return failed
+}
+
+// BatchRetry ...
+// Обрабатывает пакет, повторяя после задержки d только элементы
+// с ошибками. Элементы с постоянными ошибками (Permanent) не повторяются.
+// maxAttempts ограничивает количество вызовов fn, 0 - без ограничения;
+// nested задаёт поведение внутри другого цикла повторов
+func BatchRetry(ctx context.Context, d *Delay, maxAttempts int, nested NestedPolicy, items []interface{}, fn BatchFunc) *BatchResult {
+
+	r := &BatchResult{Errors: make([]error, len(items))}
+	ctx, scope := EnterRetry(ctx, maxAttempts, nested)
+
+	pending := make([]int, len(items))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	for len(pending) > 0 {
+
+		batch := make([]interface{}, len(pending))
+		for i, idx := range pending {
+			batch[i] = items[idx]
+		}
+
+		errs := fn(ctx, batch)
+		r.Attempts++
+
+		if len(errs) != len(batch) {
+			// Ошибка программы, повтор её не исправит
+			err := Permanent(fmt.Errorf("exponentialbackoff: batch returned %d results for %d items", len(errs), len(batch)))
+			errs = make([]error, len(batch))
+			for i := range errs {
+				errs[i] = err
+			}
+		}
+
+		var retry []int
+		for i, idx := range pending {
+			r.Errors[idx] = errs[i]
+			if errs[i] != nil && !IsPermanent(errs[i]) {
+				retry = append(retry, idx)
+			}
+		}
+		pending = retry
+
+		if len(pending) == 0 {
+			d.Decr()
+			break
+		}
+
+		if !scope.Next() {
+			break
+		}
+
+		d.Incr()
+
+		if _, err, _ := d.Backoff(ctx); err != nil {
+			r.Err = err
+			break
+		}
+	}
+
+	return r
+}
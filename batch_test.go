@@ -0,0 +1,135 @@
+package exponentialbackoff
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var errItem = errors.New("item failed")
+
+func newBatchDelay() *Delay {
+	return New(&Config{Max: 4, Factor: 2}).SetDurationUnits(time.Microsecond)
+}
+
+func TestBatchRetryPartial(t *testing.T) {
+
+	var batches [][]interface{}
+
+	// Элемент i обрабатывается с попытки i+1
+	tries := map[interface{}]int{}
+
+	r := BatchRetry(context.Background(), newBatchDelay(), 0, NestedRetry, []interface{}{"a", "b", "c"}, func(_ context.Context, items []interface{}) []error {
+
+		batches = append(batches, items)
+
+		errs := make([]error, len(items))
+		for i, item := range items {
+			tries[item]++
+			if need := map[interface{}]int{"a": 1, "b": 3, "c": 2}[item]; tries[item] < need {
+				errs[i] = errItem
+			}
+		}
+
+		return errs
+	})
+
+	want := [][]interface{}{{"a", "b", "c"}, {"b", "c"}, {"b"}}
+	if !reflect.DeepEqual(batches, want) {
+		t.Fatalf("batches: got %v, want %v", batches, want)
+	}
+
+	if r.Attempts != 3 || r.Err != nil || len(r.Failed()) != 0 {
+		t.Fatalf("result: %+v", r)
+	}
+}
+
+func TestBatchRetryPermanent(t *testing.T) {
+
+	calls := 0
+
+	r := BatchRetry(context.Background(), newBatchDelay(), 5, NestedRetry, []interface{}{1, 2, 3}, func(_ context.Context, items []interface{}) []error {
+
+		calls++
+
+		errs := make([]error, len(items))
+		for i, item := range items {
+			switch item {
+			case 1:
+				errs[i] = Permanent(errItem)
+			case 3:
+				errs[i] = errItem
+			}
+		}
+
+		return errs
+	})
+
+	if calls != 5 {
+		t.Fatalf("calls: got %d, want 5", calls)
+	}
+
+	// Ошибки по индексам исходных элементов
+	if !IsPermanent(r.Errors[0]) || r.Errors[1] != nil || r.Errors[2] != errItem {
+		t.Fatalf("errors: got %v", r.Errors)
+	}
+
+	if failed := r.Failed(); !reflect.DeepEqual(failed, []int{0, 2}) {
+		t.Fatalf("Failed: got %v, want [0 2]", failed)
+	}
+}
+
+func TestBatchRetryWrongResultCount(t *testing.T) {
+
+	calls := 0
+
+	r := BatchRetry(context.Background(), newBatchDelay(), 0, NestedRetry, []interface{}{1, 2}, func(context.Context, []interface{}) []error {
+		calls++
+		return nil
+	})
+
+	if calls != 1 {
+		t.Fatalf("calls: got %d, want 1", calls)
+	}
+
+	for i, err := range r.Errors {
+		if !IsPermanent(err) {
+			t.Fatalf("error %d: got %v, want a permanent error", i, err)
+		}
+	}
+}
+
+func TestBatchRetryNested(t *testing.T) {
+
+	calls := 0
+
+	Retry(context.Background(), newBatchDelay(), 3, NestedRetry, func(ctx context.Context) error {
+
+		r := BatchRetry(ctx, newBatchDelay(), 3, NestedDisable, []interface{}{1}, func(context.Context, []interface{}) []error {
+			calls++
+			return []error{errItem}
+		})
+
+		return r.Errors[0]
+	})
+
+	if calls != 3 {
+		t.Fatalf("calls: got %d, want 3, one per outer attempt", calls)
+	}
+}
+
+func TestBatchRetryCancelled(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := BatchRetry(ctx, New(&Config{Max: 4, Factor: 2}), 0, NestedRetry, []interface{}{1}, func(context.Context, []interface{}) []error {
+		cancel()
+		return []error{errItem}
+	})
+
+	if r.Err != context.Canceled || r.Attempts != 1 || r.Errors[0] != errItem {
+		t.Fatalf("result: %+v", r)
+	}
+}
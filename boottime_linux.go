@@ -0,0 +1,38 @@
+//go:build linux
+// +build linux
+
+package exponentialbackoff
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const clockBoottime = 7 // CLOCK_BOOTTIME
+
+var (
+	bootIDOnce sync.Once
+	bootID     string
+)
+
+func sinceBoot() (time.Duration, string, bool) {
+
+	bootIDOnce.Do(func() {
+		if b, err := os.ReadFile("/proc/sys/kernel/random/boot_id"); err == nil {
+			bootID = strings.TrimSpace(string(b))
+		}
+	})
+
+	var ts syscall.Timespec
+
+	_, _, errno := syscall.Syscall(syscall.SYS_CLOCK_GETTIME, clockBoottime, uintptr(unsafe.Pointer(&ts)), 0)
+	if errno != 0 || bootID == "" {
+		return 0, "", false
+	}
+
+	return time.Duration(ts.Nano()), bootID, true
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package exponentialbackoff
+
+import "time"
+
+func sinceBoot() (time.Duration, string, bool) {
+	return 0, "", false
+}
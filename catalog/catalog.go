@@ -0,0 +1,283 @@
+// Named backoff policies loaded from a config file
+// Каталог именованных политик задержки, загружаемый из файла
+
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+// Имя политики по умолчанию
+const DefaultPolicy = "default"
+
+var ErrUnknownPolicy = errors.New("catalog: unknown policy")
+
+// Policy ...
+// Описание политики в файле. Незаданные поля наследуются от Base
+type Policy struct {
+	Base   string `json:"base,omitempty" yaml:"base,omitempty"`     // Имя базовой политики
+	Max    *int   `json:"max,omitempty" yaml:"max,omitempty"`       // Максимальное значение задержки
+	Factor *int   `json:"factor,omitempty" yaml:"factor,omitempty"` // Коэффициент увеличения задержки
+	Unit   string `json:"unit,omitempty" yaml:"unit,omitempty"`     // Единица времени задержки, например "100ms"
+}
+
+// File ...
+// Содержимое файла каталога
+type File struct {
+	Policies map[string]Policy `json:"policies" yaml:"policies"`
+}
+
+// Resolved ...
+// Политика после применения наследования
+type Resolved struct {
+	Config exponentialbackoff.Config
+	Unit   time.Duration
+}
+
+type Catalog struct {
+	mu       sync.RWMutex
+	path     string
+	modTime  time.Time
+	policies map[string]Resolved
+}
+
+// Load ...
+// Загружает каталог из файла. Формат определяется по расширению:
+// .json - JSON, иначе YAML
+func Load(path string) (*Catalog, error) {
+
+	c := &Catalog{path: path}
+
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Parse ...
+// Возвращает каталог, не связанный с файлом
+func Parse(data []byte, isJSON bool) (*Catalog, error) {
+
+	policies, err := parse(data, isJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Catalog{policies: policies}, nil
+}
+
+// New ...
+// Возвращает новую задержку по имени политики
+func (c *Catalog) New(name string) (*exponentialbackoff.Delay, error) {
+
+	r, err := c.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := r.Config
+
+	return exponentialbackoff.New(&cfg).SetDurationUnits(r.Unit), nil
+}
+
+// Get ...
+// Возвращает политику по имени
+func (c *Catalog) Get(name string) (Resolved, error) {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	r, ok := c.policies[name]
+	if !ok {
+		return Resolved{}, fmt.Errorf("%w %q", ErrUnknownPolicy, name)
+	}
+
+	return r, nil
+}
+
+// Names ...
+// Отсортированный список имён политик
+func (c *Catalog) Names() []string {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.policies))
+	for name := range c.policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Reload ...
+// Перечитывает файл каталога. При ошибке прежние политики сохраняются
+func (c *Catalog) Reload() error {
+
+	if c.path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+
+	policies, err := parse(data, strings.EqualFold(filepath.Ext(c.path), ".json"))
+	if err != nil {
+		return fmt.Errorf("catalog: %s: %w", c.path, err)
+	}
+
+	c.mu.Lock()
+	c.policies = policies
+	c.modTime = info.ModTime()
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Watch ...
+// Проверяет время изменения файла каждые interval и перечитывает его.
+// Ошибки перезагрузки передаются в onError, если он задан.
+// Работает до отмены контекста
+func (c *Catalog) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+
+	if c.path == "" {
+		return
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		if !c.changed() {
+			continue
+		}
+
+		if err := c.Reload(); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+func (c *Catalog) changed() bool {
+
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return true
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return !info.ModTime().Equal(c.modTime)
+}
+
+func parse(data []byte, isJSON bool) (map[string]Resolved, error) {
+
+	var f File
+
+	var err error
+	if isJSON {
+		err = json.Unmarshal(data, &f)
+	} else {
+		err = yaml.Unmarshal(data, &f)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return f.Resolve()
+}
+
+// Resolve ...
+// Применяет наследование ко всем политикам файла
+func (f *File) Resolve() (map[string]Resolved, error) {
+
+	resolved := make(map[string]Resolved, len(f.Policies))
+
+	for name := range f.Policies {
+		if _, err := f.resolve(name, resolved, map[string]bool{}); err != nil {
+			return nil, err
+		}
+	}
+
+	return resolved, nil
+}
+
+func (f *File) resolve(name string, done map[string]Resolved, visiting map[string]bool) (Resolved, error) {
+
+	if r, ok := done[name]; ok {
+		return r, nil
+	}
+
+	p, ok := f.Policies[name]
+	if !ok {
+		return Resolved{}, fmt.Errorf("%w %q", ErrUnknownPolicy, name)
+	}
+
+	if visiting[name] {
+		return Resolved{}, fmt.Errorf("catalog: inheritance cycle at policy %q", name)
+	}
+	visiting[name] = true
+
+	r := Resolved{
+		Config: exponentialbackoff.Config{Factor: 1},
+		Unit:   time.Second,
+	}
+
+	if p.Base != "" {
+		base, err := f.resolve(p.Base, done, visiting)
+		if err != nil {
+			return Resolved{}, fmt.Errorf("catalog: policy %q: %w", name, err)
+		}
+		r = base
+	}
+
+	if p.Max != nil {
+		r.Config.Max = *p.Max
+	}
+
+	if p.Factor != nil {
+		r.Config.Factor = *p.Factor
+	}
+
+	if p.Unit != "" {
+		unit, err := time.ParseDuration(p.Unit)
+		if err != nil {
+			return Resolved{}, fmt.Errorf("catalog: policy %q: %w", name, err)
+		}
+		r.Unit = unit
+	}
+
+	done[name] = r
+
+	return r, nil
+}
@@ -0,0 +1,51 @@
+package catalog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsePolicy ...
+// Разбирает политику из строки вида "max=30,factor=2,unit=500ms".
+// Пары разделяются запятыми или пробелами, допустимые ключи:
+// base, max, factor, unit
+func ParsePolicy(s string) (Policy, error) {
+
+	var p Policy
+
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+
+	for _, field := range fields {
+
+		i := strings.IndexByte(field, '=')
+		if i < 0 {
+			return Policy{}, fmt.Errorf("catalog: %q: expected key=value", field)
+		}
+
+		key, value := strings.ToLower(field[:i]), field[i+1:]
+
+		switch key {
+		case "base":
+			p.Base = value
+		case "unit":
+			p.Unit = value
+		case "max", "factor":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return Policy{}, fmt.Errorf("catalog: %s: %w", key, err)
+			}
+			if key == "max" {
+				p.Max = &n
+			} else {
+				p.Factor = &n
+			}
+		default:
+			return Policy{}, fmt.Errorf("catalog: unknown key %q", key)
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,153 @@
+// Failure injection for exercising retry and backoff code paths
+// Внесение сбоев в вызовы для проверки повторов и задержек
+
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrInjected = errors.New("chaos: injected error")
+
+// Символы расписания
+const (
+	Pass    = '.' // вызов без изменений
+	Error   = 'e' // ошибка вместо вызова
+	Latency = 'l' // задержка перед вызовом
+	Panic   = 'p' // паника вместо вызова
+)
+
+type Config struct {
+	ErrorRate   float64       `json:"error_rate" yaml:"error_rate"`     // Вероятность ошибки
+	LatencyRate float64       `json:"latency_rate" yaml:"latency_rate"` // Вероятность задержки
+	PanicRate   float64       `json:"panic_rate" yaml:"panic_rate"`     // Вероятность паники
+	Latency     time.Duration `json:"latency" yaml:"latency"`           // Максимальная задержка
+	Schedule    string        `json:"schedule" yaml:"schedule"`         // Циклическое расписание, например "..e.l"; если задано, вероятности не используются
+	Seed        int64         `json:"seed" yaml:"seed"`                 // Начальное значение генератора
+	Err         error         `json:"-" yaml:"-"`                       // Вносимая ошибка, по умолчанию ErrInjected
+}
+
+type Injector struct {
+	enabled int32
+
+	mu  sync.Mutex
+	cfg Config
+	rnd *rand.Rand
+	n   int // номер вызова в расписании
+}
+
+// New ...
+// Возвращает включённый объект внесения сбоев
+func New(c Config) *Injector {
+
+	in := &Injector{enabled: 1}
+	in.SetConfig(c)
+
+	return in
+}
+
+// SetConfig ...
+// Заменяет настройки и перезапускает генератор и расписание
+func (in *Injector) SetConfig(c Config) {
+
+	if c.Err == nil {
+		c.Err = ErrInjected
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	in.cfg = c
+	in.rnd = rand.New(rand.NewSource(c.Seed))
+	in.n = 0
+}
+
+// Enable ...
+func (in *Injector) Enable() {
+	atomic.StoreInt32(&in.enabled, 1)
+}
+
+// Disable ...
+// Отключает внесение сбоев, вызовы выполняются без изменений
+func (in *Injector) Disable() {
+	atomic.StoreInt32(&in.enabled, 0)
+}
+
+// Enabled ...
+func (in *Injector) Enabled() bool {
+	return atomic.LoadInt32(&in.enabled) == 1
+}
+
+// Wrap ...
+// Возвращает функцию, вносящую сбои перед вызовом fn
+func (in *Injector) Wrap(fn func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+
+		if !in.Enabled() {
+			return fn(ctx)
+		}
+
+		latency, fault, err := in.next()
+
+		if latency > 0 {
+
+			t := time.NewTimer(latency)
+
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			}
+		}
+
+		switch fault {
+		case Panic:
+			panic("chaos: injected panic")
+		case Error:
+			return err
+		}
+
+		return fn(ctx)
+	}
+}
+
+// next определяет сбой для очередного вызова
+func (in *Injector) next() (time.Duration, byte, error) {
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	c := in.cfg
+
+	if c.Schedule != "" {
+
+		step := c.Schedule[in.n%len(c.Schedule)]
+		in.n++
+
+		if step == Latency {
+			return c.Latency, Pass, c.Err
+		}
+
+		return 0, step, c.Err
+	}
+
+	var latency time.Duration
+	if c.Latency > 0 && in.rnd.Float64() < c.LatencyRate {
+		latency = time.Duration(in.rnd.Int63n(int64(c.Latency)) + 1)
+	}
+
+	switch {
+	case in.rnd.Float64() < c.PanicRate:
+		return latency, Panic, c.Err
+	case in.rnd.Float64() < c.ErrorRate:
+		return latency, Error, c.Err
+	}
+
+	return latency, Pass, c.Err
+}
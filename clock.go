@@ -0,0 +1,55 @@
+package exponentialbackoff
+
+import "time"
+
+// Clock ...
+// Источник времени задержки
+type Clock interface {
+	// Now возвращает текущее время
+	Now() time.Time
+	// SinceBoot возвращает время с загрузки системы, включая сон,
+	// и идентификатор загрузки. ok = false, если недоступно
+	SinceBoot() (since time.Duration, bootID string, ok bool)
+}
+
+type systemClock struct{}
+
+// SystemClock ...
+// Системные часы
+var SystemClock Clock = systemClock{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) SinceBoot() (time.Duration, string, bool) {
+	return sinceBoot()
+}
+
+// SetClock ...
+// Установить источник времени, nil - системные часы
+func (d *Delay) SetClock(c Clock) *Delay {
+	if d.isInit {
+		d.clock = c
+	}
+
+	return d
+}
+
+func (d *Delay) now() time.Time {
+
+	if d.clock != nil {
+		return d.clock.Now()
+	}
+
+	return time.Now()
+}
+
+func (d *Delay) getClock() Clock {
+
+	if d.clock != nil {
+		return d.clock
+	}
+
+	return SystemClock
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gitlab.alx/rb/exponentialbackoff/v1/catalog"
+)
+
+const maxInt = int(^uint(0) >> 1)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+	severityInfo    = "info" // не влияет на код завершения
+)
+
+type finding struct {
+	Source   string `json:"source"`
+	Policy   string `json:"policy"`
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+type linter struct {
+	maxDelay time.Duration
+	maxSteps int
+	info     bool // сообщать о свойствах, общих для всех политик
+	findings []finding
+}
+
+// failed сообщает, есть ли находки, кроме информационных
+func (l *linter) failed() bool {
+
+	for _, f := range l.findings {
+		if f.Severity != severityInfo {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *linter) report(source, policy, check, severity, format string, args ...interface{}) {
+	l.findings = append(l.findings, finding{
+		Source:   source,
+		Policy:   policy,
+		Check:    check,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) check(source, policy string, r catalog.Resolved) {
+
+	max, factor, unit := r.Config.Max, r.Config.Factor, r.Unit
+
+	// Политика не задаёт ни случайного разброса, ни числа попыток:
+	// Delay не поддерживает ни то, ни другое, поэтому это не ошибка политики
+	if l.info {
+		l.report(source, policy, "no-jitter", severityInfo,
+			"delays have no jitter, clients failing together retry in lockstep")
+		l.report(source, policy, "unbounded-attempts", severityInfo,
+			"the policy does not limit attempts, callers retry until they give up themselves")
+	}
+
+	if max < 0 {
+		l.report(source, policy, "invalid-max", severityError,
+			"max %d is negative and will be replaced with 0", max)
+	}
+
+	if factor < 1 {
+		l.report(source, policy, "invalid-factor", severityError,
+			"factor %d is less than 1 and will be replaced with 1", factor)
+	}
+
+	if unit <= 0 {
+		l.report(source, policy, "invalid-unit", severityError,
+			"unit %s is not positive, the delay never waits", unit)
+		return
+	}
+
+	if max <= 0 {
+		l.report(source, policy, "no-backoff", severityWarning,
+			"max is 0, the delay never waits")
+		return
+	}
+
+	if factor > 1 && max > (maxInt-factor)/factor {
+		l.report(source, policy, "overflow", severityError,
+			"max %d with factor %d overflows int on Incr", max, factor)
+	}
+
+	if int64(max) > math.MaxInt64/int64(unit) {
+		l.report(source, policy, "overflow", severityError,
+			"max %d of %s overflows time.Duration", max, unit)
+	} else if d := time.Duration(max) * unit; d > l.maxDelay {
+		l.report(source, policy, "long-delay", severityWarning,
+			"longest delay %s exceeds %s", d, l.maxDelay)
+	}
+
+	if factor <= 1 {
+		l.report(source, policy, "linear-growth", severityWarning,
+			"factor %d grows the delay by one unit per failure", factor)
+	}
+
+	if factor >= max {
+		l.report(source, policy, "immediate-cap", severityWarning,
+			"factor %d reaches max %d on the first failure", factor, max)
+	}
+
+	if max > l.maxSteps {
+		l.report(source, policy, "slow-recovery", severityWarning,
+			"recovering from max %d takes %d Decr calls, more than %d", max, max, l.maxSteps)
+	}
+}
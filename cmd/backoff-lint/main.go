@@ -0,0 +1,126 @@
+// backoff-lint checks backoff policies for risky configurations
+// Проверка политик задержки на опасные настройки
+//
+// Использование:
+//
+//	backoff-lint [-json] [-info=false] [-max-delay 5m] [-max-steps 100] [-e "max=30,factor=2"]... [file]...
+//
+// Код завершения 1, если найдены ошибки или предупреждения.
+// Информационные находки (no-jitter, unbounded-attempts) на него не влияют
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gitlab.alx/rb/exponentialbackoff/v1/catalog"
+)
+
+type exprs []string
+
+func (e *exprs) String() string {
+	return strings.Join(*e, "; ")
+}
+
+func (e *exprs) Set(s string) error {
+	*e = append(*e, s)
+	return nil
+}
+
+func main() {
+
+	var (
+		expressions exprs
+		asJSON      = flag.Bool("json", false, "print findings as JSON")
+		maxDelay    = flag.Duration("max-delay", 5*time.Minute, "longest acceptable delay (max * unit)")
+		maxSteps    = flag.Int("max-steps", 100, "longest acceptable recovery, in Decr calls")
+		info        = flag.Bool("info", true, "report informational findings, which do not affect the exit code")
+	)
+
+	flag.Var(&expressions, "e", "policy string, e.g. \"max=30,factor=2,unit=1s\" (repeatable)")
+	flag.Parse()
+
+	if len(expressions) == 0 && flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	l := &linter{
+		maxDelay: *maxDelay,
+		maxSteps: *maxSteps,
+		info:     *info,
+	}
+
+	for i, e := range expressions {
+
+		p, err := catalog.ParsePolicy(e)
+		if err != nil {
+			fatal(err)
+		}
+
+		name := fmt.Sprintf("-e#%d", i+1)
+		f := catalog.File{Policies: map[string]catalog.Policy{name: p}}
+
+		resolved, err := f.Resolve()
+		if err != nil {
+			fatal(err)
+		}
+
+		l.check(e, name, resolved[name])
+	}
+
+	for _, path := range flag.Args() {
+
+		c, err := catalog.Load(path)
+		if err != nil {
+			fatal(err)
+		}
+
+		for _, name := range c.Names() {
+			r, _ := c.Get(name)
+			l.check(path, name, r)
+		}
+	}
+
+	if *asJSON {
+		err := writeJSON(os.Stdout, l.findings)
+		if err != nil {
+			fatal(err)
+		}
+	} else {
+		writeText(os.Stdout, l.findings)
+	}
+
+	if l.failed() {
+		os.Exit(1)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "backoff-lint:", err)
+	os.Exit(2)
+}
+
+func writeJSON(w io.Writer, findings []finding) error {
+
+	if findings == nil {
+		findings = []finding{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(findings)
+}
+
+func writeText(w io.Writer, findings []finding) {
+	for _, f := range findings {
+		fmt.Fprintf(w, "%s: %s: %s: %s (%s)\n", f.Source, f.Policy, f.Severity, f.Message, f.Check)
+	}
+}
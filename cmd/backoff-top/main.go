@@ -0,0 +1,146 @@
+// backoff-top shows live backoff state of a process
+// Просмотр состояния задержек процесса в реальном времени
+//
+// Опрашивает отладочный обработчик delaydebug и выводит таблицу.
+// Клавиши: n, d, i, w - сортировка по имени, задержке, Incr, суммарному
+// ожиданию; r - обратный порядок; q - выход.
+//
+// Использование:
+//
+//	backoff-top [-interval 1s] [-sort delay] http://localhost:6060/debug/backoff
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"gitlab.alx/rb/exponentialbackoff/v1/delaydebug"
+)
+
+func main() {
+
+	var (
+		interval = flag.Duration("interval", time.Second, "refresh interval")
+		sortBy   = flag.String("sort", sortDelay, "sort column: name, delay, incr, waited")
+		history  = flag.Int("history", 30, "sparkline length")
+	)
+
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	v := newView(*sortBy, *history)
+	keys, restore := readKeys()
+	defer restore()
+
+	defer fmt.Print(showCursor)
+	fmt.Print(hideCursor)
+
+	client := &http.Client{Timeout: *interval}
+	t := time.NewTicker(*interval)
+	defer t.Stop()
+
+	var err error
+
+	for refresh := true; ; {
+
+		if refresh {
+			var entries []delaydebug.Entry
+			if entries, err = fetch(ctx, client, flag.Arg(0)); err == nil {
+				v.update(entries)
+			}
+		}
+
+		v.render(os.Stdout, flag.Arg(0), err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			refresh = true
+		case k, ok := <-keys:
+			if !ok || k == 'q' {
+				return
+			}
+			v.key(k)
+			refresh = false
+		}
+	}
+}
+
+func fetch(ctx context.Context, client *http.Client, url string) ([]delaydebug.Entry, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+
+	var entries []delaydebug.Entry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// readKeys переводит терминал в посимвольный режим и возвращает
+// канал нажатых клавиш и функцию восстановления режима терминала.
+// Если терминал недоступен, клавиши не читаются
+func readKeys() (<-chan byte, func()) {
+
+	keys := make(chan byte)
+
+	if err := stty("cbreak", "-echo"); err != nil {
+		return keys, func() {}
+	}
+
+	go func() {
+
+		defer close(keys)
+
+		buf := make([]byte, 1)
+		for {
+			if _, err := os.Stdin.Read(buf); err != nil {
+				return
+			}
+			keys <- buf[0]
+		}
+	}()
+
+	return keys, func() {
+		stty("-cbreak", "echo")
+	}
+}
+
+func stty(args ...string) error {
+
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+
+	return cmd.Run()
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"gitlab.alx/rb/exponentialbackoff/v1/delaydebug"
+)
+
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	hideCursor  = "\x1b[?25l"
+	showCursor  = "\x1b[?25h"
+	reverse     = "\x1b[7m"
+	bold        = "\x1b[1m"
+	red         = "\x1b[31m"
+	normal      = "\x1b[0m"
+)
+
+const (
+	sortName   = "name"
+	sortDelay  = "delay"
+	sortIncr   = "incr"
+	sortWaited = "waited"
+)
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+type view struct {
+	sortBy   string
+	reversed bool
+	length   int
+	entries  []delaydebug.Entry
+	history  map[string][]time.Duration
+	updated  time.Time
+}
+
+func newView(sortBy string, length int) *view {
+
+	if length < 1 {
+		length = 1
+	}
+
+	return &view{
+		sortBy:  sortBy,
+		length:  length,
+		history: make(map[string][]time.Duration),
+	}
+}
+
+func (v *view) update(entries []delaydebug.Entry) {
+
+	seen := make(map[string]bool, len(entries))
+
+	for _, e := range entries {
+
+		h := append(v.history[e.Name], e.Duration)
+		if len(h) > v.length {
+			h = h[len(h)-v.length:]
+		}
+
+		v.history[e.Name] = h
+		seen[e.Name] = true
+	}
+
+	for name := range v.history {
+		if !seen[name] {
+			delete(v.history, name)
+		}
+	}
+
+	v.entries = entries
+	v.updated = time.Now()
+}
+
+func (v *view) key(k byte) {
+	switch k {
+	case 'n':
+		v.sortBy = sortName
+	case 'd':
+		v.sortBy = sortDelay
+	case 'i':
+		v.sortBy = sortIncr
+	case 'w':
+		v.sortBy = sortWaited
+	case 'r':
+		v.reversed = !v.reversed
+	}
+}
+
+func (v *view) less(a, b delaydebug.Entry) bool {
+	switch v.sortBy {
+	case sortDelay:
+		if a.Duration != b.Duration {
+			return a.Duration > b.Duration
+		}
+	case sortIncr:
+		if a.Stats.Incr != b.Stats.Incr {
+			return a.Stats.Incr > b.Stats.Incr
+		}
+	case sortWaited:
+		if a.Stats.Waited != b.Stats.Waited {
+			return a.Stats.Waited > b.Stats.Waited
+		}
+	}
+
+	return a.Name < b.Name
+}
+
+func (v *view) render(out io.Writer, source string, err error) {
+
+	entries := append([]delaydebug.Entry(nil), v.entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if v.reversed {
+			return v.less(entries[j], entries[i])
+		}
+		return v.less(entries[i], entries[j])
+	})
+
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+
+	fmt.Fprint(w, clearScreen)
+	fmt.Fprintf(w, "%s%s%s  %s  sort: %s\n", bold, source, normal, v.updated.Format("15:04:05"), v.sortBy)
+
+	if err != nil {
+		fmt.Fprintf(w, "%s%v%s\n", red, err, normal)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s %s %s %s %s %s %s\n",
+		v.header(sortName, "%-24s", "NAME"),
+		v.header(sortDelay, "%12s", "DELAY"),
+		v.header(sortIncr, "%8s", "INCR"),
+		v.header("", "%8s", "DECR"),
+		v.header("", "%8s", "RESET"),
+		v.header(sortWaited, "%12s", "WAITED"),
+		"HISTORY",
+	)
+
+	for _, e := range entries {
+
+		line := fmt.Sprintf("%-24.24s %12s %8d %8d %8d %12s %s",
+			e.Name, e.Duration, e.Stats.Incr, e.Stats.Decr, e.Stats.Reset,
+			e.Stats.Waited.Round(time.Millisecond), sparkline(v.history[e.Name]))
+
+		if e.Delay > 0 {
+			line = red + line + normal
+		}
+
+		fmt.Fprintln(w, line)
+	}
+}
+
+func (v *view) header(column, format, title string) string {
+
+	s := fmt.Sprintf(format, title)
+	if column != "" && column == v.sortBy {
+		return reverse + s + normal
+	}
+
+	return s
+}
+
+func sparkline(h []time.Duration) string {
+
+	var max time.Duration
+	for _, d := range h {
+		if d > max {
+			max = d
+		}
+	}
+
+	line := make([]rune, len(h))
+	for i, d := range h {
+		if max == 0 {
+			line[i] = sparks[0]
+			continue
+		}
+		line[i] = sparks[int(int64(d)*int64(len(sparks)-1)/int64(max))]
+	}
+
+	return string(line)
+}
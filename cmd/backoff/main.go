@@ -0,0 +1,57 @@
+// backoff is a command line companion of the exponentialbackoff package
+// Утилита командной строки для пакета exponentialbackoff
+//
+// Использование:
+//
+//	backoff wait-for [flags] tcp HOST:PORT
+//	backoff wait-for [flags] http [-status 200] URL
+//	backoff wait-for [flags] file PATH
+//	backoff wait-for [flags] exec COMMAND [ARG]...
+//
+// Коды завершения: 0 - готово, 1 - истекло время ожидания,
+// 2 - ошибка в аргументах.
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	exitOK      = 0
+	exitTimeout = 1
+	exitUsage   = 2
+)
+
+func main() {
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(exitUsage)
+	}
+
+	switch os.Args[1] {
+	case "wait-for":
+		os.Exit(waitFor(os.Args[2:]))
+	case "help", "-h", "-help", "--help":
+		usage()
+		os.Exit(exitOK)
+	default:
+		fmt.Fprintf(os.Stderr, "backoff: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(exitUsage)
+	}
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, `usage:
+  backoff wait-for [flags] tcp HOST:PORT
+  backoff wait-for [flags] http [-status 200] URL
+  backoff wait-for [flags] file PATH
+  backoff wait-for [flags] exec COMMAND [ARG]...
+
+Run "backoff wait-for -h" for flags.
+Exit codes: 0 ready, 1 timed out, 2 usage error.
+`)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+type check func(ctx context.Context) error
+
+func waitFor(args []string) int {
+
+	fs := flag.NewFlagSet("wait-for", flag.ContinueOnError)
+
+	var (
+		timeout        = fs.Duration("timeout", time.Minute, "overall timeout, 0 - wait forever")
+		attemptTimeout = fs.Duration("attempt-timeout", 5*time.Second, "timeout of a single check")
+		max            = fs.Int("max", 10, "max delay, in units")
+		factor         = fs.Int("factor", 2, "delay factor")
+		unit           = fs.Duration("unit", time.Second, "delay unit")
+		quiet          = fs.Bool("q", false, "do not report failed checks")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "backoff wait-for: mode is required: tcp, http, file or exec")
+		return exitUsage
+	}
+
+	c, target, err := newCheck(fs.Arg(0), fs.Args()[1:], *attemptTimeout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "backoff wait-for:", err)
+		return exitUsage
+	}
+
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	d := exponentialbackoff.New(&exponentialbackoff.Config{Max: *max, Factor: *factor}).SetDurationUnits(*unit)
+
+	for {
+
+		err := c(ctx)
+		if err == nil {
+			return exitOK
+		}
+
+		d.Incr()
+
+		if !*quiet {
+			fmt.Fprintf(os.Stderr, "backoff wait-for: %s: %v, retrying in %s\n", target, err, d.Duration())
+		}
+
+		if _, err, _ := d.Backoff(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "backoff wait-for: %s: timed out\n", target)
+			return exitTimeout
+		}
+	}
+}
+
+func newCheck(mode string, args []string, attemptTimeout time.Duration) (check, string, error) {
+
+	switch mode {
+	case "tcp":
+		if len(args) != 1 {
+			return nil, "", errors.New("tcp: expected HOST:PORT")
+		}
+		return tcpCheck(args[0], attemptTimeout), args[0], nil
+
+	case "http":
+		fs := flag.NewFlagSet("http", flag.ContinueOnError)
+		status := fs.Int("status", http.StatusOK, "expected status code")
+		if err := fs.Parse(args); err != nil {
+			return nil, "", err
+		}
+		if fs.NArg() != 1 {
+			return nil, "", errors.New("http: expected URL")
+		}
+		return httpCheck(fs.Arg(0), *status, attemptTimeout), fs.Arg(0), nil
+
+	case "file":
+		if len(args) != 1 {
+			return nil, "", errors.New("file: expected PATH")
+		}
+		return fileCheck(args[0]), args[0], nil
+
+	case "exec":
+		if len(args) > 0 && args[0] == "--" {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			return nil, "", errors.New("exec: expected COMMAND")
+		}
+		return execCheck(args, attemptTimeout), args[0], nil
+	}
+
+	return nil, "", fmt.Errorf("unknown mode %q", mode)
+}
+
+func tcpCheck(addr string, timeout time.Duration) check {
+	return func(ctx context.Context) error {
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		var dialer net.Dialer
+
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	}
+}
+
+func httpCheck(url string, status int, timeout time.Duration) check {
+
+	client := &http.Client{Timeout: timeout}
+
+	return func(ctx context.Context) error {
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != status {
+			return fmt.Errorf("status %d, want %d", resp.StatusCode, status)
+		}
+
+		return nil
+	}
+}
+
+func fileCheck(path string) check {
+	return func(context.Context) error {
+		_, err := os.Stat(path)
+		return err
+	}
+}
+
+func execCheck(args []string, timeout time.Duration) check {
+	return func(ctx context.Context) error {
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+
+		return cmd.Run()
+	}
+}
@@ -0,0 +1,83 @@
+// backoffd serves named backoff delays over a Unix domain socket
+// Демон, хранящий именованные задержки и обслуживающий их через Unix-сокет
+//
+// Использование:
+//
+//	backoffd [-socket /run/backoffd.sock] [-catalog policies.yaml] [-max 60] [-factor 2] [-unit 1s]
+
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+	"gitlab.alx/rb/exponentialbackoff/v1/backoffd"
+	"gitlab.alx/rb/exponentialbackoff/v1/catalog"
+)
+
+func main() {
+
+	var (
+		socket      = flag.String("socket", "/run/backoffd.sock", "unix socket path")
+		catalogPath = flag.String("catalog", "", "policy catalog file; names missing from it use the \"default\" policy")
+		max         = flag.Int("max", 60, "max delay when no catalog is given")
+		factor      = flag.Int("factor", 2, "delay factor when no catalog is given")
+		unit        = flag.Duration("unit", time.Second, "delay unit when no catalog is given")
+	)
+
+	flag.Parse()
+
+	newDelay := func(string) (*exponentialbackoff.Delay, error) {
+		return exponentialbackoff.New(&exponentialbackoff.Config{Max: *max, Factor: *factor}).SetDurationUnits(*unit), nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *catalogPath != "" {
+
+		c, err := catalog.Load(*catalogPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		go c.Watch(ctx, 5*time.Second, func(err error) {
+			log.Println(err)
+		})
+
+		newDelay = func(name string) (*exponentialbackoff.Delay, error) {
+
+			d, err := c.New(name)
+			if err == nil {
+				return d, nil
+			}
+
+			return c.New(catalog.DefaultPolicy)
+		}
+	}
+
+	// Сокет мог остаться после аварийного завершения.
+	// Сокет работающего демона не удаляется
+	if conn, err := net.Dial("unix", *socket); err == nil {
+		conn.Close()
+		log.Fatalf("%s: another backoffd is listening", *socket)
+	}
+	os.Remove(*socket)
+
+	l, err := net.Listen("unix", *socket)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(*socket)
+
+	if err := backoffd.NewServer(newDelay).Serve(ctx, l); err != nil {
+		log.Println(err)
+	}
+}
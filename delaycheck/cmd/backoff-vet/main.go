@@ -0,0 +1,18 @@
+// backoff-vet reports misuse of exponentialbackoff.Delay
+// Проверка кода на ошибки использования exponentialbackoff.Delay
+//
+// Использование:
+//
+//	backoff-vet ./...
+
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"gitlab.alx/rb/exponentialbackoff/v1/delaycheck"
+)
+
+func main() {
+	singlechecker.Main(delaycheck.Analyzer)
+}
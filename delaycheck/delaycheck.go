@@ -0,0 +1,296 @@
+// Static analysis of exponentialbackoff.Delay misuse
+// Статический анализ ошибок использования exponentialbackoff.Delay
+//
+// Пакет вынесен в отдельный модуль: golang.org/x/tools, совместимый с
+// текущими версиями Go, требует go 1.25, а основной модуль остаётся на go 1.16.
+// go vet и go test корневого модуля его не проверяют, их нужно запускать
+// в каталоге delaycheck.
+
+package delaycheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const pkgPath = "gitlab.alx/rb/exponentialbackoff/v1"
+
+const doc = `check for misuse of exponentialbackoff.Delay
+
+The delaycheck analyzer reports:
+  - Delay values copied by value (Delay embeds sync.RWMutex);
+  - calls to Backoff whose error result is ignored;
+  - calls to Backoff with context.Background() or context.TODO()
+    inside HTTP handlers, where the request context should be used;
+  - calls to SetDelay from goroutines, SetDelay is not synchronised.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "delaycheck",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+
+	in := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	filter := []ast.Node{
+		(*ast.AssignStmt)(nil),
+		(*ast.CallExpr)(nil),
+		(*ast.ExprStmt)(nil),
+		(*ast.FuncType)(nil),
+		(*ast.RangeStmt)(nil),
+		(*ast.ReturnStmt)(nil),
+		(*ast.ValueSpec)(nil),
+	}
+
+	in.WithStack(filter, func(n ast.Node, push bool, stack []ast.Node) bool {
+
+		if !push {
+			return true
+		}
+
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			checkCopies(pass, assignedValues(n))
+			checkIgnoredError(pass, n)
+		case *ast.ValueSpec:
+			checkCopies(pass, n.Values)
+		case *ast.ReturnStmt:
+			checkCopies(pass, n.Results)
+		case *ast.RangeStmt:
+			if n.Value != nil && isDelay(pass.TypesInfo.TypeOf(n.Value)) {
+				pass.Reportf(n.Value.Pos(), "range copies exponentialbackoff.Delay by value, which contains sync.RWMutex")
+			}
+		case *ast.FuncType:
+			checkSignature(pass, n)
+		case *ast.ExprStmt:
+			if call, ok := n.X.(*ast.CallExpr); ok && isMethod(pass, call, "Backoff") {
+				pass.Reportf(call.Pos(), "error result of Delay.Backoff is ignored")
+			}
+		case *ast.CallExpr:
+			checkCopies(pass, n.Args)
+			checkHandlerContext(pass, n, stack)
+			checkSetDelay(pass, n, stack)
+		}
+
+		return true
+	})
+
+	return nil, nil
+}
+
+// isDelay сообщает, является ли t типом exponentialbackoff.Delay (не указателем)
+func isDelay(t types.Type) bool {
+
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == "Delay"
+}
+
+// isMethod сообщает, является ли call вызовом метода name у Delay
+func isMethod(pass *analysis.Pass, call *ast.CallExpr, name string) bool {
+
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil || fn.Name() != name {
+		return false
+	}
+
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+
+	t := recv.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+
+	return isDelay(t)
+}
+
+func checkCopies(pass *analysis.Pass, exprs []ast.Expr) {
+
+	for _, e := range exprs {
+
+		if !isDelay(pass.TypesInfo.TypeOf(e)) {
+			continue
+		}
+
+		// Композитный литерал и результат вызова создают новое значение
+		switch unparen(e).(type) {
+		case *ast.CompositeLit, *ast.CallExpr:
+			continue
+		}
+
+		pass.Reportf(e.Pos(), "exponentialbackoff.Delay copied by value, which contains sync.RWMutex; use *Delay")
+	}
+}
+
+// assignedValues возвращает значения присваивания, кроме присваиваемых "_"
+func assignedValues(as *ast.AssignStmt) []ast.Expr {
+
+	if len(as.Lhs) != len(as.Rhs) {
+		return as.Rhs
+	}
+
+	var values []ast.Expr
+
+	for i, lhs := range as.Lhs {
+		if id, ok := lhs.(*ast.Ident); ok && id.Name == "_" {
+			continue
+		}
+		values = append(values, as.Rhs[i])
+	}
+
+	return values
+}
+
+func checkSignature(pass *analysis.Pass, ft *ast.FuncType) {
+
+	lists := []*ast.FieldList{ft.Params, ft.Results}
+
+	for _, list := range lists {
+
+		if list == nil {
+			continue
+		}
+
+		for _, field := range list.List {
+			if isDelay(pass.TypesInfo.TypeOf(field.Type)) {
+				pass.Reportf(field.Type.Pos(), "exponentialbackoff.Delay passed by value, which contains sync.RWMutex; use *Delay")
+			}
+		}
+	}
+}
+
+func checkIgnoredError(pass *analysis.Pass, as *ast.AssignStmt) {
+
+	if len(as.Rhs) != 1 || len(as.Lhs) != 3 {
+		return
+	}
+
+	call, ok := as.Rhs[0].(*ast.CallExpr)
+	if !ok || !isMethod(pass, call, "Backoff") {
+		return
+	}
+
+	if id, ok := as.Lhs[1].(*ast.Ident); ok && id.Name == "_" {
+		pass.Reportf(call.Pos(), "error result of Delay.Backoff is ignored")
+	}
+}
+
+func checkHandlerContext(pass *analysis.Pass, call *ast.CallExpr, stack []ast.Node) {
+
+	if !isMethod(pass, call, "Backoff") || len(call.Args) != 1 {
+		return
+	}
+
+	arg, ok := unparen(call.Args[0]).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+
+	fn := typeutil.StaticCallee(pass.TypesInfo, arg)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != "context" {
+		return
+	}
+
+	if fn.Name() != "Background" && fn.Name() != "TODO" {
+		return
+	}
+
+	if !inHandler(pass, stack) {
+		return
+	}
+
+	pass.Reportf(arg.Pos(), "Delay.Backoff called with context.%s() in an HTTP handler; use the request context", fn.Name())
+}
+
+// inHandler сообщает, находится ли узел внутри функции
+// с параметрами (http.ResponseWriter, *http.Request)
+func inHandler(pass *analysis.Pass, stack []ast.Node) bool {
+
+	for i := len(stack) - 1; i >= 0; i-- {
+
+		var ft *ast.FuncType
+
+		switch f := stack[i].(type) {
+		case *ast.FuncDecl:
+			ft = f.Type
+		case *ast.FuncLit:
+			ft = f.Type
+		default:
+			continue
+		}
+
+		if isHandlerSignature(pass, ft) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isHandlerSignature(pass *analysis.Pass, ft *ast.FuncType) bool {
+
+	var writer, request bool
+
+	for _, field := range ft.Params.List {
+
+		t := pass.TypesInfo.TypeOf(field.Type)
+		if t == nil {
+			continue
+		}
+
+		switch t.String() {
+		case "net/http.ResponseWriter":
+			writer = true
+		case "*net/http.Request":
+			request = true
+		}
+	}
+
+	return writer && request
+}
+
+func checkSetDelay(pass *analysis.Pass, call *ast.CallExpr, stack []ast.Node) {
+
+	if !isMethod(pass, call, "SetDelay") {
+		return
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+
+		if _, ok := stack[i].(*ast.FuncDecl); ok {
+			return
+		}
+
+		if _, ok := stack[i].(*ast.GoStmt); ok {
+			pass.Reportf(call.Pos(), "Delay.SetDelay called from a goroutine; SetDelay is not synchronised")
+			return
+		}
+	}
+}
+
+func unparen(e ast.Expr) ast.Expr {
+
+	for {
+		p, ok := e.(*ast.ParenExpr)
+		if !ok {
+			return e
+		}
+		e = p.X
+	}
+}
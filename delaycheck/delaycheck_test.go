@@ -0,0 +1,13 @@
+package delaycheck_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"gitlab.alx/rb/exponentialbackoff/v1/delaycheck"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), delaycheck.Analyzer, "a")
+}
@@ -0,0 +1,91 @@
+package a
+
+import (
+	"context"
+	"net/http"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+var global = exponentialbackoff.Delay{} // композитный литерал: не копия
+
+func byValue(d exponentialbackoff.Delay) {} // want `exponentialbackoff.Delay passed by value`
+
+func returnsValue() exponentialbackoff.Delay { // want `exponentialbackoff.Delay passed by value`
+	return exponentialbackoff.Delay{}
+}
+
+func byPointer(d *exponentialbackoff.Delay) *exponentialbackoff.Delay {
+	return d
+}
+
+func copies(p *exponentialbackoff.Delay, ds []exponentialbackoff.Delay) {
+
+	c := *p // want `exponentialbackoff.Delay copied by value`
+	_ = c   // присваивание "_" не копирует
+
+	var v = *p // want `exponentialbackoff.Delay copied by value`
+	_ = v
+
+	for _, d := range ds { // want `range copies exponentialbackoff.Delay by value`
+		_ = d
+	}
+
+	for i := range ds {
+		_ = &ds[i]
+	}
+
+	lit := exponentialbackoff.Delay{}
+	_ = &lit
+}
+
+func backoff(ctx context.Context, d *exponentialbackoff.Delay) {
+
+	d.Backoff(ctx) // want `error result of Delay.Backoff is ignored`
+
+	_, _, _ = d.Backoff(ctx) // want `error result of Delay.Backoff is ignored`
+
+	if _, err, _ := d.Backoff(ctx); err != nil {
+		return
+	}
+}
+
+func handler(d *exponentialbackoff.Delay) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if _, err, _ := d.Backoff(context.Background()); err != nil { // want `Delay.Backoff called with context.Background\(\) in an HTTP handler`
+			return
+		}
+
+		if _, err, _ := d.Backoff(context.TODO()); err != nil { // want `Delay.Backoff called with context.TODO\(\) in an HTTP handler`
+			return
+		}
+
+		if _, err, _ := d.Backoff(r.Context()); err != nil {
+			return
+		}
+	}
+}
+
+func notHandler(d *exponentialbackoff.Delay) error {
+
+	_, err, _ := d.Backoff(context.Background())
+
+	return err
+}
+
+func setDelay(d *exponentialbackoff.Delay) {
+
+	d.SetDelay(1)
+
+	go func() {
+		d.SetDelay(2) // want `Delay.SetDelay called from a goroutine`
+	}()
+
+	go d.SetDelay(3) // want `Delay.SetDelay called from a goroutine`
+
+	f := func() {
+		d.SetDelay(4)
+	}
+	f()
+}
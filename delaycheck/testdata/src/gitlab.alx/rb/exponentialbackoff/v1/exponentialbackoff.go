@@ -0,0 +1,27 @@
+// Заглушка пакета exponentialbackoff для тестов анализатора
+
+package exponentialbackoff
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	Max    int
+	Factor int
+}
+
+type Delay struct {
+	sync.RWMutex
+	i int
+}
+
+func New(c *Config) *Delay { return &Delay{} }
+
+func (d *Delay) Incr() *Delay { return d }
+
+func (d *Delay) SetDelay(v int) *Delay { d.i = v; return d }
+
+func (d *Delay) Backoff(ctx context.Context) (bool, error, time.Duration) { return false, nil, 0 }
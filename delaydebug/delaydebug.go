@@ -0,0 +1,111 @@
+// Debug endpoint exposing the state of named delays
+// Отладочный HTTP-обработчик с состоянием именованных задержек
+
+package delaydebug
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+// Entry ...
+// Состояние одной задержки в ответе обработчика
+type Entry struct {
+	Name     string                   `json:"name"`
+	Delay    int                      `json:"delay"`
+	Duration time.Duration            `json:"duration"`
+	Stats    exponentialbackoff.Stats `json:"stats"`
+}
+
+type Registry struct {
+	mu     sync.RWMutex
+	delays map[string]*exponentialbackoff.Delay
+}
+
+// Реестр по умолчанию, используемый функциями пакета
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry ...
+func NewRegistry() *Registry {
+	return &Registry{
+		delays: make(map[string]*exponentialbackoff.Delay),
+	}
+}
+
+// Register ...
+// Регистрирует задержку под именем name, заменяя прежнюю
+func (r *Registry) Register(name string, d *exponentialbackoff.Delay) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.delays[name] = d
+}
+
+// Unregister ...
+func (r *Registry) Unregister(name string) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.delays, name)
+}
+
+// Snapshot ...
+// Состояние зарегистрированных задержек, отсортированное по имени
+func (r *Registry) Snapshot() []Entry {
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]Entry, 0, len(r.delays))
+
+	for name, d := range r.delays {
+		entries = append(entries, Entry{
+			Name:     name,
+			Delay:    d.GetDelay(),
+			Duration: d.Duration(),
+			Stats:    d.Stats(),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries
+}
+
+// ServeHTTP ...
+// Отдаёт Snapshot в формате JSON
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(r.Snapshot()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Register ...
+// Регистрирует задержку в реестре по умолчанию
+func Register(name string, d *exponentialbackoff.Delay) {
+	DefaultRegistry.Register(name, d)
+}
+
+// Unregister ...
+// Удаляет задержку из реестра по умолчанию
+func Unregister(name string) {
+	DefaultRegistry.Unregister(name)
+}
+
+// Handler ...
+// Обработчик реестра по умолчанию
+func Handler() http.Handler {
+	return DefaultRegistry
+}
@@ -21,6 +21,26 @@ type Delay struct {
 	max           int
 	factor        int
 	durationUnits time.Duration
+	policy        Policy
+	wheel         *TimerWheel
+	stats         Stats
+	clock         Clock
+	failedAt      Timestamp // время последнего вызова Incr
+	reserved      Timestamp // последний зарезервированный момент, см. Reserve
+	seen          time.Time // последний момент по часам задержки, см. adjustClock
+	slowStart     *SlowStart
+	recoveredAt   time.Time // время начала разгона, см. SetSlowStart
+	credit        float64   // накопленная доля пропуска вызовов при разгоне
+}
+
+// Stats ...
+// Счётчики вызовов задержки
+type Stats struct {
+	Incr     int64         `json:"incr"`     // Количество вызовов Incr
+	Decr     int64         `json:"decr"`     // Количество вызовов Decr
+	Reset    int64         `json:"reset"`    // Количество вызовов Reset
+	Backoffs int64         `json:"backoffs"` // Количество выполненных задержек
+	Waited   time.Duration `json:"waited"`   // Суммарное время задержек
 }
 
 // New ...
@@ -55,6 +75,13 @@ func (d *Delay) Incr() *Delay {
 	d.Lock()
 	defer d.Unlock()
 
+	now := d.now()
+	d.adjustClock(now)
+
+	d.stats.Incr++
+	d.failedAt = d.stampAt(now, now, d.getClock())
+	d.reserved = Timestamp{}
+
 	if d.i == d.max {
 		return d
 	}
@@ -79,6 +106,8 @@ func (d *Delay) Decr() *Delay {
 	d.Lock()
 	defer d.Unlock()
 
+	d.stats.Decr++
+
 	if d.i == 0 {
 		return d
 	}
@@ -102,6 +131,10 @@ func (d *Delay) Reset() *Delay {
 	d.Lock()
 	defer d.Unlock()
 
+	d.stats.Reset++
+	d.reserved = Timestamp{}
+	d.startRamp()
+
 	if d.i != 0 {
 		d.i = 0
 	}
@@ -111,6 +144,10 @@ func (d *Delay) Reset() *Delay {
 
 // GetDelay ...
 func (d *Delay) GetDelay() int {
+
+	d.RLock()
+	defer d.RUnlock()
+
 	return d.i
 }
 
@@ -121,14 +158,119 @@ func (d *Delay) SetDelay(v int) *Delay {
 	return d
 }
 
+// Duration ...
+// Текущая задержка в единицах времени
+func (d *Delay) Duration() time.Duration {
+
+	if !d.isInit {
+		return 0
+	}
+
+	d.RLock()
+	defer d.RUnlock()
+
+	return d.duration()
+}
+
+// duration вычисляет текущую задержку. Вызывается под блокировкой
+func (d *Delay) duration() time.Duration {
+
+	if d.i <= 0 {
+		return 0
+	}
+
+	du := time.Duration(d.i) * d.durationUnits
+	if d.policy != nil {
+		du = d.policy(du)
+	}
+
+	du = scaleDuration(du)
+
+	if du < 0 {
+		return 0
+	}
+
+	return du
+}
+
+// SetPolicy ...
+// Установить политику, преобразующую задержку.
+// Политика применяется только к установленной задержке
+func (d *Delay) SetPolicy(p Policy) *Delay {
+
+	if !d.isInit {
+		return d
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.policy = p
+
+	return d
+}
+
+// GetMax ...
+func (d *Delay) GetMax() int {
+
+	d.RLock()
+	defer d.RUnlock()
+
+	return d.max
+}
+
+// SetMax ...
+// Установить максимальное значение задержки
+func (d *Delay) SetMax(v int) *Delay {
+
+	if !d.isInit {
+		return d
+	}
+
+	if v < 0 {
+		v = 0
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.max = v
+
+	if d.i > d.max {
+		d.i = d.max
+	}
+
+	return d
+}
+
+// GetFactor ...
+func (d *Delay) GetFactor() int {
+	return d.factor
+}
+
+// GetDurationUnits ...
+func (d *Delay) GetDurationUnits() time.Duration {
+
+	d.RLock()
+	defer d.RUnlock()
+
+	return d.durationUnits
+}
+
 // SetDurationUnits
 // Установить единицу времени, в которой будет измеряться задержка
 //
 func (d *Delay) SetDurationUnits(du time.Duration) *Delay {
-	if d.isInit {
-		d.durationUnits = du
+
+	if !d.isInit {
+		return d
 	}
 
+	d.Lock()
+	defer d.Unlock()
+
+	d.durationUnits = du
+
 	return d
 }
 
@@ -140,6 +282,9 @@ func (d *Delay) IssetDelay() bool {
 		return false
 	}
 
+	d.RLock()
+	defer d.RUnlock()
+
 	return d.i > 0
 }
 
@@ -164,10 +309,48 @@ func (d *Delay) Backoff(ctx context.Context) (bool, error, time.Duration) {
 
 	if isd {
 
+		d.sleep(ctx, d.Duration())
+
+		d.Lock()
+		d.stats.Backoffs++
+		d.stats.Waited += time.Since(ts)
+		d.Unlock()
+	}
+	return isd, ctx.Err(), time.Since(ts)
+}
+
+// SetTimerWheel ...
+// Установить колесо таймеров для Backoff, nil - отдельный таймер на каждый вызов
+func (d *Delay) SetTimerWheel(w *TimerWheel) *Delay {
+	if d.isInit {
+		d.wheel = w
+	}
+
+	return d
+}
+
+func (d *Delay) sleep(ctx context.Context, du time.Duration) {
+
+	if d.wheel != nil {
 		select {
-		case <-time.After(time.Duration(d.GetDelay()) * d.durationUnits):
+		case <-d.wheel.After(du):
 		case <-ctx.Done():
 		}
+		return
 	}
-	return isd, ctx.Err(), time.Since(ts)
+
+	select {
+	case <-time.After(du):
+	case <-ctx.Done():
+	}
+}
+
+// Stats ...
+// Возвращает счётчики вызовов задержки
+func (d *Delay) Stats() Stats {
+
+	d.RLock()
+	defer d.RUnlock()
+
+	return d.stats
 }
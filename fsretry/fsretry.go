@@ -0,0 +1,164 @@
+// Filesystem operations with exponential backoff
+// Файловые операции с повтором при временных ошибках
+
+package fsretry
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+// Временные ошибки, после которых операцию имеет смысл повторить
+var transient = []syscall.Errno{
+	syscall.EBUSY,
+	syscall.ETXTBSY,
+	syscall.EAGAIN,
+	syscall.EINTR,
+	syscall.ESTALE,
+}
+
+// Переопределяется в тестах
+var rename = os.Rename
+
+type FS struct {
+	delay       *exponentialbackoff.Delay
+	maxAttempts int // 0 - без ограничения, до отмены контекста
+	nested      exponentialbackoff.NestedPolicy
+}
+
+// New ...
+// Возвращает объект файловых операций, повторяющий
+// операции с задержкой d не более maxAttempts раз
+func New(d *exponentialbackoff.Delay, maxAttempts int) *FS {
+
+	if maxAttempts < 0 {
+		maxAttempts = 0
+	}
+
+	return &FS{
+		delay:       d,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// SetNested ...
+// Установить поведение внутри другого цикла повторов,
+// например exponentialbackoff.NestedDisable
+func (f *FS) SetNested(p exponentialbackoff.NestedPolicy) *FS {
+	f.nested = p
+	return f
+}
+
+// IsTransient ...
+// Является ли ошибка временной
+func IsTransient(err error) bool {
+
+	for _, errno := range transient {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Rename ...
+// os.Rename с повтором
+func (f *FS) Rename(ctx context.Context, oldpath, newpath string) error {
+	return f.do(ctx, func() error {
+		return rename(oldpath, newpath)
+	})
+}
+
+// Remove ...
+// os.Remove с повтором
+func (f *FS) Remove(ctx context.Context, name string) error {
+	return f.do(ctx, func() error {
+		return os.Remove(name)
+	})
+}
+
+// OpenFile ...
+// os.OpenFile с повтором
+func (f *FS) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (*os.File, error) {
+
+	var file *os.File
+
+	err := f.do(ctx, func() (err error) {
+		file, err = os.OpenFile(name, flag, perm)
+		return err
+	})
+
+	return file, err
+}
+
+// WriteFileAtomic ...
+// Записывает данные во временный файл в том же каталоге
+// и переименовывает его в name. Повторяется целиком
+func (f *FS) WriteFileAtomic(ctx context.Context, name string, data []byte, perm os.FileMode) error {
+	return f.do(ctx, func() error {
+		return writeFileAtomic(name, data, perm)
+	})
+}
+
+func (f *FS) do(ctx context.Context, op func() error) error {
+
+	ctx, scope := exponentialbackoff.EnterRetry(ctx, f.maxAttempts, f.nested)
+
+	for {
+
+		err := op()
+		if err == nil {
+			f.delay.Decr()
+			return nil
+		}
+
+		if !IsTransient(err) || !scope.Next() {
+			return err
+		}
+
+		f.delay.Incr()
+
+		if _, berr, _ := f.delay.Backoff(ctx); berr != nil {
+			return err
+		}
+	}
+}
+
+func writeFileAtomic(name string, data []byte, perm os.FileMode) (err error) {
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".tmp*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return rename(tmp.Name(), name)
+}
@@ -0,0 +1,142 @@
+package fsretry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+func newFS(maxAttempts int) *FS {
+	d := exponentialbackoff.New(&exponentialbackoff.Config{Max: 4, Factor: 2}).SetDurationUnits(time.Microsecond)
+	return New(d, maxAttempts)
+}
+
+// busyRename подменяет rename: первые n вызовов завершаются EBUSY,
+// остальные выполняются. Возвращает счётчик вызовов
+func busyRename(t *testing.T, n int) *int {
+
+	calls := 0
+
+	rename = func(oldpath, newpath string) error {
+		calls++
+		if calls <= n {
+			return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EBUSY}
+		}
+		return os.Rename(oldpath, newpath)
+	}
+
+	t.Cleanup(func() {
+		rename = os.Rename
+	})
+
+	return &calls
+}
+
+// names возвращает имена файлов каталога
+func names(t *testing.T, dir string) []string {
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+
+	return names
+}
+
+func TestRenameRetriesBusy(t *testing.T) {
+
+	dir := t.TempDir()
+	old, renamed := filepath.Join(dir, "old"), filepath.Join(dir, "new")
+
+	if err := os.WriteFile(old, []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := busyRename(t, 2)
+
+	if err := newFS(0).Rename(context.Background(), old, renamed); err != nil {
+		t.Fatal(err)
+	}
+
+	if *calls != 3 {
+		t.Fatalf("rename calls: got %d, want 3", *calls)
+	}
+
+	if got := names(t, dir); len(got) != 1 || got[0] != "new" {
+		t.Fatalf("directory: got %v, want [new]", got)
+	}
+}
+
+func TestWriteFileAtomicRetriesBusy(t *testing.T) {
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "file")
+
+	calls := busyRename(t, 2)
+
+	if err := newFS(0).WriteFileAtomic(context.Background(), name, []byte("data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if *calls != 3 {
+		t.Fatalf("rename calls: got %d, want 3", *calls)
+	}
+
+	// Временные файлы неудачных попыток удалены
+	if got := names(t, dir); len(got) != 1 || got[0] != "file" {
+		t.Fatalf("directory: got %v, want [file]", got)
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil || string(data) != "data" {
+		t.Fatalf("content: got %q, %v", data, err)
+	}
+
+	if fi, _ := os.Stat(name); fi.Mode().Perm() != 0o600 {
+		t.Fatalf("mode: got %v, want 0600", fi.Mode().Perm())
+	}
+}
+
+func TestWriteFileAtomicGivesUp(t *testing.T) {
+
+	dir := t.TempDir()
+
+	calls := busyRename(t, 100)
+
+	err := newFS(3).WriteFileAtomic(context.Background(), filepath.Join(dir, "file"), []byte("data"), 0o600)
+	if !IsTransient(err) {
+		t.Fatalf("got %v, want EBUSY", err)
+	}
+
+	if *calls != 3 {
+		t.Fatalf("rename calls: got %d, want 3", *calls)
+	}
+
+	if got := names(t, dir); len(got) != 0 {
+		t.Fatalf("temporary files left: %v", got)
+	}
+}
+
+func TestNotTransient(t *testing.T) {
+
+	start := time.Now()
+
+	err := newFS(0).Remove(context.Background(), filepath.Join(t.TempDir(), "missing"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("got %v, want not exist", err)
+	}
+
+	if IsTransient(err) || time.Since(start) > time.Second {
+		t.Fatal("ENOENT was retried")
+	}
+}
@@ -1,3 +1,5 @@
 module gitlab.alx/rb/exponentialbackoff/v1
 
 go 1.16
+
+require gopkg.in/yaml.v3 v3.0.1
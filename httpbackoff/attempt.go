@@ -0,0 +1,188 @@
+package httpbackoff
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+// Заголовок с номером повтора запроса; у первой попытки отсутствует
+const AttemptHeader = "X-Retry-Attempt"
+
+// SetAttempt ...
+// Устанавливает номер повтора запроса, 0 - первая попытка
+func SetAttempt(req *http.Request, attempt int) {
+
+	if attempt <= 0 {
+		req.Header.Del(AttemptHeader)
+		return
+	}
+
+	req.Header.Set(AttemptHeader, strconv.Itoa(attempt))
+}
+
+// Attempt ...
+// Номер повтора запроса, 0 - первая попытка или заголовок некорректен
+func Attempt(req *http.Request) int {
+
+	n, err := strconv.Atoi(req.Header.Get(AttemptHeader))
+	if err != nil || n < 0 {
+		return 0
+	}
+
+	return n
+}
+
+type Transport struct {
+	base        http.RoundTripper
+	delay       func(*http.Request) (*exponentialbackoff.Delay, error)
+	maxAttempts int
+	nested      exponentialbackoff.NestedPolicy
+	retryable   func(*http.Response, error) bool
+}
+
+// NewTransport ...
+// Возвращает транспорт, повторяющий запросы после задержки,
+// выбранной функцией delay (например, Router.Delay), не более
+// maxAttempts попыток. Повторы помечаются заголовком AttemptHeader.
+// Запросы с телом без GetBody не повторяются
+func NewTransport(base http.RoundTripper, delay func(*http.Request) (*exponentialbackoff.Delay, error), maxAttempts int) *Transport {
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &Transport{
+		base:        base,
+		delay:       delay,
+		maxAttempts: maxAttempts,
+		retryable:   Retryable,
+	}
+}
+
+// SetRetryable ...
+// Установить функцию, определяющую, нужно ли повторить запрос
+func (t *Transport) SetRetryable(fn func(*http.Response, error) bool) *Transport {
+	t.retryable = fn
+	return t
+}
+
+// SetNested ...
+// Установить поведение внутри другого цикла повторов,
+// например exponentialbackoff.NestedDisable
+func (t *Transport) SetNested(p exponentialbackoff.NestedPolicy) *Transport {
+	t.nested = p
+	return t
+}
+
+// Retryable ...
+// Повторяются сетевые ошибки и ответы 429, 502, 503, 504
+func Retryable(resp *http.Response, err error) bool {
+
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// RoundTrip ...
+// Реализует http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	d, err := t.delay(req)
+	if err != nil {
+		return nil, err
+	}
+
+	canRetry := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	ctx, scope := exponentialbackoff.EnterRetry(req.Context(), t.maxAttempts, t.nested)
+
+	for attempt := 0; ; attempt++ {
+
+		r := req.WithContext(ctx)
+		if attempt > 0 {
+
+			r = req.Clone(ctx)
+			SetAttempt(r, attempt)
+
+			if req.GetBody != nil {
+				if r.Body, err = req.GetBody(); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		resp, err := t.base.RoundTrip(r)
+
+		if !t.retryable(resp, err) {
+			d.Decr()
+			return resp, err
+		}
+
+		if !canRetry || !scope.Next() {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		d.Incr()
+
+		if _, berr, _ := d.Backoff(ctx); berr != nil {
+			return nil, berr
+		}
+	}
+}
+
+// ShedConfig ...
+// Пороги отклонения запросов при перегрузке
+type ShedConfig struct {
+	RetryLimit int           // При стольких обрабатываемых запросах повторы отклоняются
+	Limit      int           // При стольких обрабатываемых запросах отклоняются все, 0 - без ограничения
+	RetryAfter time.Duration // Значение заголовка Retry-After ответа 503
+}
+
+// Shed ...
+// Обработчик, отклоняющий при перегрузке сначала повторы
+// (запросы с заголовком AttemptHeader), а затем и первые попытки
+func Shed(c ShedConfig, next http.Handler) http.Handler {
+
+	var inFlight int64
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		n := int(atomic.AddInt64(&inFlight, 1))
+		defer atomic.AddInt64(&inFlight, -1)
+
+		overloaded := c.Limit > 0 && n > c.Limit
+		if Attempt(r) > 0 && c.RetryLimit > 0 && n > c.RetryLimit {
+			overloaded = true
+		}
+
+		if overloaded {
+			if c.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int((c.RetryAfter+time.Second-1)/time.Second)))
+			}
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
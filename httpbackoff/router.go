@@ -0,0 +1,242 @@
+// Backoff helpers for HTTP clients and servers
+// Задержки для HTTP-клиентов и серверов
+
+package httpbackoff
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+// Route ...
+// Правило выбора политики по запросу
+type Route struct {
+	Method  string // Метод, "" или "*" - любой
+	Host    string // Шаблон хоста в синтаксисе path.Match, "" - любой
+	Pattern string // Шаблон пути в синтаксисе path.Match; оканчивающийся на "/" совпадает со всеми путями ниже, "" - любой
+	Policy  string // Имя политики
+}
+
+type route struct {
+	Route
+	order int
+	delay *exponentialbackoff.Delay
+}
+
+type Router struct {
+	newDelay     func(policy string) (*exponentialbackoff.Delay, error)
+	mostSpecific bool
+
+	mu       sync.Mutex
+	routes   []*route
+	fallback *route
+}
+
+// NewRouter ...
+// Возвращает маршрутизатор политик. Задержки создаются функцией newDelay
+// по имени политики, например catalog.Catalog.New, по одной на правило.
+// Запросы, не совпавшие ни с одним правилом, получают политику defaultPolicy
+func NewRouter(newDelay func(policy string) (*exponentialbackoff.Delay, error), defaultPolicy string) *Router {
+	return &Router{
+		newDelay: newDelay,
+		fallback: &route{Route: Route{Policy: defaultPolicy}, order: -1},
+	}
+}
+
+// SetMostSpecific ...
+// Выбирать наиболее конкретное правило вместо первого совпавшего
+func (r *Router) SetMostSpecific(v bool) *Router {
+	r.mostSpecific = v
+	return r
+}
+
+// Handle ...
+// Добавляет правило
+func (r *Router) Handle(rt Route) error {
+
+	if _, err := path.Match(rt.Pattern, "/"); err != nil {
+		return fmt.Errorf("httpbackoff: pattern %q: %w", rt.Pattern, err)
+	}
+
+	if _, err := path.Match(rt.Host, ""); err != nil {
+		return fmt.Errorf("httpbackoff: host %q: %w", rt.Host, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.routes = append(r.routes, &route{Route: rt, order: len(r.routes)})
+
+	return nil
+}
+
+// Match ...
+// Возвращает правило для запроса. ok = false, если подошла политика по умолчанию
+func (r *Router) Match(req *http.Request) (Route, bool) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rt := r.match(req)
+
+	return rt.Route, rt != r.fallback
+}
+
+// Delay ...
+// Возвращает задержку правила, совпавшего с запросом
+func (r *Router) Delay(req *http.Request) (*exponentialbackoff.Delay, error) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rt := r.match(req)
+
+	if rt.delay == nil {
+		d, err := r.newDelay(rt.Policy)
+		if err != nil {
+			return nil, err
+		}
+		rt.delay = d
+	}
+
+	return rt.delay, nil
+}
+
+func (r *Router) match(req *http.Request) *route {
+
+	var best *route
+
+	for _, rt := range r.routes {
+
+		if !rt.matches(req) {
+			continue
+		}
+
+		if !r.mostSpecific {
+			return rt
+		}
+
+		if best == nil || rt.specificity().greater(best.specificity()) {
+			best = rt
+		}
+	}
+
+	if best == nil {
+		return r.fallback
+	}
+
+	return best
+}
+
+func (rt *route) matches(req *http.Request) bool {
+
+	if rt.Method != "" && rt.Method != "*" && !strings.EqualFold(rt.Method, req.Method) {
+		return false
+	}
+
+	if rt.Host != "" {
+
+		host := req.URL.Hostname()
+		if host == "" {
+			host = req.Host
+			if i := strings.LastIndexByte(host, ':'); i >= 0 && !strings.Contains(host[i:], "]") {
+				host = host[:i]
+			}
+		}
+
+		if ok, _ := path.Match(strings.ToLower(rt.Host), strings.ToLower(host)); !ok {
+			return false
+		}
+	}
+
+	if rt.Pattern == "" {
+		return true
+	}
+
+	p := req.URL.Path
+	if p == "" {
+		p = "/"
+	}
+
+	if strings.HasSuffix(rt.Pattern, "/") {
+		return matchSubtree(rt.Pattern, p)
+	}
+
+	ok, _ := path.Match(rt.Pattern, p)
+
+	return ok
+}
+
+// matchSubtree сопоставляет начальные сегменты пути с шаблоном, оканчивающимся на "/"
+func matchSubtree(pattern, p string) bool {
+
+	n := strings.Count(pattern, "/")
+	segments := strings.SplitAfterN(p, "/", n+1)
+
+	if len(segments) < n {
+		return false
+	}
+
+	prefix := strings.Join(segments[:n], "")
+	if !strings.HasSuffix(prefix, "/") {
+		return false
+	}
+
+	ok, _ := path.Match(pattern, prefix)
+
+	return ok
+}
+
+type specificity struct {
+	exact   int // 2 - путь без шаблонов, 1 - с шаблонами, 0 - поддерево или любой путь
+	literal int // длина пути без символов шаблона
+	host    int
+	method  int
+	order   int
+}
+
+func (rt *route) specificity() specificity {
+
+	s := specificity{
+		literal: len(strings.NewReplacer("*", "", "?", "", "[", "", "]", "").Replace(rt.Pattern)),
+		order:   rt.order,
+	}
+
+	switch {
+	case rt.Pattern == "", strings.HasSuffix(rt.Pattern, "/"):
+	case strings.ContainsAny(rt.Pattern, "*?["):
+		s.exact = 1
+	default:
+		s.exact = 2
+	}
+
+	if rt.Host != "" {
+		s.host = 1
+	}
+
+	if rt.Method != "" && rt.Method != "*" {
+		s.method = 1
+	}
+
+	return s
+}
+
+// greater сравнивает конкретность; при равенстве выигрывает более раннее правило
+func (s specificity) greater(o specificity) bool {
+
+	a := []int{s.exact, s.literal, s.host, s.method, -s.order}
+	b := []int{o.exact, o.literal, o.host, o.method, -o.order}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] > b[i]
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,137 @@
+package httpbackoff
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+func newTestRouter(t *testing.T, mostSpecific bool, routes ...Route) *Router {
+
+	r := NewRouter(func(string) (*exponentialbackoff.Delay, error) {
+		return exponentialbackoff.New(&exponentialbackoff.Config{Max: 4, Factor: 2}), nil
+	}, "default").SetMostSpecific(mostSpecific)
+
+	for _, rt := range routes {
+		if err := r.Handle(rt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return r
+}
+
+// policy возвращает политику, выбранную для запроса
+func policy(r *Router, method, url string) string {
+	rt, _ := r.Match(httptest.NewRequest(method, url, nil))
+	return rt.Policy
+}
+
+func TestRouterOrder(t *testing.T) {
+
+	routes := []Route{
+		{Pattern: "/api/", Policy: "api"},
+		{Pattern: "/api/users/*", Policy: "users"},
+		{Method: "POST", Pattern: "/api/users/*", Policy: "users-post"},
+		{Pattern: "/api/users/me", Policy: "me"},
+	}
+
+	tests := []struct {
+		method, url         string
+		first, mostSpecific string
+	}{
+		{"GET", "http://x/api/users/me", "api", "me"},
+		{"GET", "http://x/api/users/42", "api", "users"},
+		{"POST", "http://x/api/users/42", "api", "users-post"},
+		{"GET", "http://x/api/orders", "api", "api"},
+		{"GET", "http://x/other", "default", "default"},
+	}
+
+	first := newTestRouter(t, false, routes...)
+	specific := newTestRouter(t, true, routes...)
+
+	for _, tt := range tests {
+
+		if got := policy(first, tt.method, tt.url); got != tt.first {
+			t.Errorf("first match %s %s: got %q, want %q", tt.method, tt.url, got, tt.first)
+		}
+
+		if got := policy(specific, tt.method, tt.url); got != tt.mostSpecific {
+			t.Errorf("most specific %s %s: got %q, want %q", tt.method, tt.url, got, tt.mostSpecific)
+		}
+	}
+}
+
+func TestRouterSubtree(t *testing.T) {
+
+	r := newTestRouter(t, false,
+		Route{Pattern: "/v*/items/", Policy: "items"},
+		Route{Pattern: "/static/", Policy: "static"},
+	)
+
+	tests := []struct {
+		url, want string
+	}{
+		{"http://x/v1/items/", "items"},
+		{"http://x/v2/items/42/parts", "items"},
+		{"http://x/v1/items", "default"},
+		{"http://x/v1/other/items/", "default"},
+		{"http://x/static/css/site.css", "static"},
+		{"http://x/static", "default"},
+		{"http://x/staticfiles/a", "default"},
+	}
+
+	for _, tt := range tests {
+		if got := policy(r, "GET", tt.url); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestRouterHost(t *testing.T) {
+
+	r := newTestRouter(t, true,
+		Route{Host: "api.example.com", Policy: "api"},
+		Route{Host: "*.example.com", Pattern: "/health", Policy: "health"},
+		Route{Pattern: "/health", Policy: "any-health"},
+	)
+
+	tests := []struct {
+		url, want string
+	}{
+		{"http://api.example.com/anything", "api"},
+		{"http://API.Example.com:8080/", "api"},
+		{"http://api.example.com/health", "health"},
+		{"http://www.example.com/health", "health"},
+		{"http://www.example.org/health", "any-health"},
+		{"http://www.example.com/", "default"},
+	}
+
+	for _, tt := range tests {
+		if got := policy(r, "GET", tt.url); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestRouterDelay(t *testing.T) {
+
+	r := newTestRouter(t, false, Route{Pattern: "/a", Policy: "a"})
+
+	a1, _ := r.Delay(httptest.NewRequest("GET", "/a", nil))
+	a2, _ := r.Delay(httptest.NewRequest("GET", "/a", nil))
+	b, _ := r.Delay(httptest.NewRequest("GET", "/b", nil))
+
+	if a1 != a2 {
+		t.Fatal("one rule returned different delays")
+	}
+
+	if a1 == b {
+		t.Fatal("a rule and the default policy share a delay")
+	}
+
+	if err := r.Handle(Route{Pattern: "["}); err == nil {
+		t.Fatal("Handle accepted a malformed pattern")
+	}
+}
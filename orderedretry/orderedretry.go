@@ -0,0 +1,201 @@
+// Per-key ordered retries with exponential backoff
+// Повтор обработки с сохранением порядка элементов одного ключа
+
+package orderedretry
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+var (
+	ErrQueueFull = errors.New("orderedretry: key queue is full")
+	ErrClosed    = errors.New("orderedretry: retrier is closed")
+)
+
+// Handler ...
+// Обработчик элемента. При ошибке элемент обрабатывается повторно
+// после задержки, а следующие элементы того же ключа ждут
+type Handler func(ctx context.Context, key string, item interface{}) error
+
+type OrderedRetrier struct {
+	ctx         context.Context
+	handler     Handler
+	newDelay    func() *exponentialbackoff.Delay
+	maxBuffered int
+	maxAttempts int
+	nested      exponentialbackoff.NestedPolicy
+	onDrop      func(key string, item interface{}, err error)
+
+	mu     sync.Mutex
+	queues map[string]*queue
+	closed bool
+	wg     sync.WaitGroup
+}
+
+type queue struct {
+	items []interface{}
+	delay *exponentialbackoff.Delay
+}
+
+// New ...
+// Возвращает объект повторов. Для каждого ключа с элементами
+// в очереди создаётся задержка newDelay и горутина обработки.
+// В очереди ключа хранится не более maxBuffered элементов.
+// Отмена ctx прерывает обработку
+func New(ctx context.Context, handler Handler, newDelay func() *exponentialbackoff.Delay, maxBuffered int) *OrderedRetrier {
+
+	if maxBuffered < 1 {
+		maxBuffered = 1
+	}
+
+	return &OrderedRetrier{
+		ctx:         ctx,
+		handler:     handler,
+		newDelay:    newDelay,
+		maxBuffered: maxBuffered,
+		queues:      make(map[string]*queue),
+	}
+}
+
+// SetMaxAttempts ...
+// Установить количество попыток обработки элемента, 0 - без ограничения
+func (r *OrderedRetrier) SetMaxAttempts(n int) *OrderedRetrier {
+	r.maxAttempts = n
+	return r
+}
+
+// SetNested ...
+// Установить поведение внутри другого цикла повторов, в котором
+// создан контекст New, например exponentialbackoff.NestedDisable
+func (r *OrderedRetrier) SetNested(p exponentialbackoff.NestedPolicy) *OrderedRetrier {
+	r.nested = p
+	return r
+}
+
+// OnDrop ...
+// Установить обработчик элементов, отброшенных после исчерпания
+// попыток или отмены контекста
+func (r *OrderedRetrier) OnDrop(fn func(key string, item interface{}, err error)) *OrderedRetrier {
+	r.onDrop = fn
+	return r
+}
+
+// Submit ...
+// Ставит элемент в очередь ключа
+func (r *OrderedRetrier) Submit(key string, item interface{}) error {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return ErrClosed
+	}
+
+	q, ok := r.queues[key]
+	if !ok {
+		q = &queue{delay: r.newDelay()}
+		r.queues[key] = q
+		r.wg.Add(1)
+		go r.run(key, q)
+	}
+
+	if len(q.items) >= r.maxBuffered {
+		return ErrQueueFull
+	}
+
+	q.items = append(q.items, item)
+
+	return nil
+}
+
+// Len ...
+// Количество элементов в очереди ключа, включая обрабатываемый
+func (r *OrderedRetrier) Len(key string) int {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if q, ok := r.queues[key]; ok {
+		return len(q.items)
+	}
+
+	return 0
+}
+
+// Close ...
+// Прекращает приём элементов и ждёт обработки уже поставленных
+func (r *OrderedRetrier) Close() {
+
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+
+	r.wg.Wait()
+}
+
+func (r *OrderedRetrier) run(key string, q *queue) {
+
+	defer r.wg.Done()
+
+	for {
+
+		r.mu.Lock()
+		if len(q.items) == 0 {
+			delete(r.queues, key)
+			r.mu.Unlock()
+			return
+		}
+		item := q.items[0]
+		r.mu.Unlock()
+
+		r.process(key, item, q.delay)
+
+		r.mu.Lock()
+		q.items[0] = nil
+		q.items = q.items[1:]
+		r.mu.Unlock()
+	}
+}
+
+// process обрабатывает элемент до успеха, исчерпания попыток или отмены контекста
+func (r *OrderedRetrier) process(key string, item interface{}, d *exponentialbackoff.Delay) {
+
+	ctx, scope := exponentialbackoff.EnterRetry(r.ctx, r.maxAttempts, r.nested)
+
+	for {
+
+		// После отмены контекста оставшиеся элементы отбрасываются без обработки
+		if err := ctx.Err(); err != nil {
+			r.drop(key, item, err)
+			return
+		}
+
+		err := r.handler(ctx, key, item)
+		if err == nil {
+			d.Reset()
+			return
+		}
+
+		if !scope.Next() {
+			r.drop(key, item, err)
+			return
+		}
+
+		d.Incr()
+
+		if _, berr, _ := d.Backoff(ctx); berr != nil {
+			r.drop(key, item, berr)
+			return
+		}
+	}
+}
+
+func (r *OrderedRetrier) drop(key string, item interface{}, err error) {
+	if r.onDrop != nil {
+		r.onDrop(key, item, err)
+	}
+}
@@ -0,0 +1,141 @@
+package orderedretry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+var errFailed = errors.New("failed")
+
+func newDelay() *exponentialbackoff.Delay {
+	return exponentialbackoff.New(&exponentialbackoff.Config{Max: 4, Factor: 2}).SetDurationUnits(time.Microsecond)
+}
+
+func TestOrder(t *testing.T) {
+
+	var (
+		got   []int
+		fails = 2
+	)
+
+	r := New(context.Background(), func(_ context.Context, _ string, item interface{}) error {
+
+		if fails > 0 {
+			fails--
+			return errFailed
+		}
+
+		got = append(got, item.(int))
+
+		return nil
+	}, newDelay, 10)
+
+	for i := 1; i <= 3; i++ {
+		if err := r.Submit("key", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r.Close()
+
+	if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Fatalf("got %v, want [1 2 3]", got)
+	}
+}
+
+func TestMaxAttemptsDrops(t *testing.T) {
+
+	var (
+		calls   = map[int]int{}
+		dropped []interface{}
+		dropErr error
+	)
+
+	r := New(context.Background(), func(_ context.Context, _ string, item interface{}) error {
+
+		calls[item.(int)]++
+
+		if item.(int) == 1 {
+			return errFailed
+		}
+
+		return nil
+	}, newDelay, 10).
+		SetMaxAttempts(3).
+		OnDrop(func(_ string, item interface{}, err error) {
+			dropped = append(dropped, item)
+			dropErr = err
+		})
+
+	for i := 1; i <= 2; i++ {
+		if err := r.Submit("key", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r.Close()
+
+	if calls[1] != 3 || calls[2] != 1 {
+		t.Fatalf("got attempts %v, want map[1:3 2:1]", calls)
+	}
+
+	// Следующий элемент обрабатывается после отброшенного
+	if len(dropped) != 1 || dropped[0] != 1 || dropErr != errFailed {
+		t.Fatalf("dropped %v with %v, want [1] with %v", dropped, dropErr, errFailed)
+	}
+}
+
+func TestCancelledDropsWithoutHandler(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var (
+		calls   int32
+		dropped []interface{}
+		mu      sync.Mutex
+	)
+
+	release := make(chan struct{})
+
+	r := New(ctx, func(context.Context, string, interface{}) error {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return errFailed
+	}, newDelay, 10).OnDrop(func(_ string, item interface{}, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != context.Canceled {
+			t.Errorf("item %v dropped with %v, want context.Canceled", item, err)
+		}
+		dropped = append(dropped, item)
+	})
+
+	for i := 1; i <= 3; i++ {
+		if err := r.Submit("key", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Первый элемент обрабатывается во время отмены
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	close(release)
+
+	r.Close()
+
+	if calls != 1 {
+		t.Fatalf("handler called %d times, want 1", calls)
+	}
+
+	if len(dropped) != 3 {
+		t.Fatalf("dropped %v, want all 3 items", dropped)
+	}
+}
@@ -0,0 +1,35 @@
+package exponentialbackoff
+
+import "errors"
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent ...
+// Помечает ошибку как постоянную: повторять операцию не нужно
+func Permanent(err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// IsPermanent ...
+// Помечена ли ошибка как постоянная
+func IsPermanent(err error) bool {
+
+	var p *permanentError
+
+	return errors.As(err, &p)
+}
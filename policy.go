@@ -0,0 +1,121 @@
+package exponentialbackoff
+
+import "time"
+
+// Policy ...
+// Преобразует задержку (значение задержки, умноженное на единицу времени)
+// в фактическую длительность ожидания
+type Policy func(du time.Duration) time.Duration
+
+// Base ...
+// Политика без преобразования
+func Base(du time.Duration) time.Duration {
+	return du
+}
+
+// Fixed ...
+// Политика с постоянной длительностью, например подсказкой сервера
+func Fixed(v time.Duration) Policy {
+	return func(time.Duration) time.Duration {
+		return v
+	}
+}
+
+// Max ...
+// Наибольшая из длительностей политик, без политик - 0
+func Max(policies ...Policy) Policy {
+	return func(du time.Duration) time.Duration {
+
+		var max time.Duration
+
+		for i, p := range policies {
+			if v := p(du); i == 0 || v > max {
+				max = v
+			}
+		}
+
+		return max
+	}
+}
+
+// Min ...
+// Наименьшая из длительностей политик, без политик - 0
+func Min(policies ...Policy) Policy {
+	return func(du time.Duration) time.Duration {
+
+		var min time.Duration
+
+		for i, p := range policies {
+			if v := p(du); i == 0 || v < min {
+				min = v
+			}
+		}
+
+		return min
+	}
+}
+
+// Scale ...
+// Длительность политики, умноженная на f
+func Scale(p Policy, f float64) Policy {
+	return func(du time.Duration) time.Duration {
+		return time.Duration(float64(p(du)) * f)
+	}
+}
+
+// Clamp ...
+// Длительность политики, ограниченная отрезком [lo, hi]
+func Clamp(p Policy, lo, hi time.Duration) Policy {
+	return func(du time.Duration) time.Duration {
+
+		v := p(du)
+
+		if v < lo {
+			return lo
+		}
+
+		if v > hi {
+			return hi
+		}
+
+		return v
+	}
+}
+
+// Offset ...
+// Длительность политики, увеличенная на v
+func Offset(p Policy, v time.Duration) Policy {
+	return func(du time.Duration) time.Duration {
+		return p(du) + v
+	}
+}
+
+// Sum ...
+// Сумма длительностей политик
+func Sum(policies ...Policy) Policy {
+	return func(du time.Duration) time.Duration {
+
+		var sum time.Duration
+
+		for _, p := range policies {
+			sum += p(du)
+		}
+
+		return sum
+	}
+}
+
+// Schedule ...
+// Длительности ожидания после каждого из n последовательных Incr
+// для задержки с настройками c, единицей времени du и политикой p
+func Schedule(c Config, du time.Duration, p Policy, n int) []time.Duration {
+
+	d := New(&c).SetDurationUnits(du).SetPolicy(p)
+
+	schedule := make([]time.Duration, 0, n)
+	for i := 0; i < n; i++ {
+		schedule = append(schedule, d.Incr().Duration())
+	}
+
+	return schedule
+}
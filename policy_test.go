@@ -0,0 +1,67 @@
+package exponentialbackoff
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+
+	const s = time.Second
+
+	c := Config{Max: 8, Factor: 2}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   []time.Duration
+	}{
+		{"nil", nil, []time.Duration{2 * s, 6 * s, 8 * s, 8 * s}},
+		{"base", Base, []time.Duration{2 * s, 6 * s, 8 * s, 8 * s}},
+		{"fixed", Fixed(3 * s), []time.Duration{3 * s, 3 * s, 3 * s, 3 * s}},
+		{"max", Max(Base, Fixed(5*s)), []time.Duration{5 * s, 6 * s, 8 * s, 8 * s}},
+		{"max single", Max(Base), []time.Duration{2 * s, 6 * s, 8 * s, 8 * s}},
+		{"max empty", Max(), []time.Duration{0, 0, 0, 0}},
+		{"min", Min(Base, Fixed(5*s)), []time.Duration{2 * s, 5 * s, 5 * s, 5 * s}},
+		{"min empty", Min(), []time.Duration{0, 0, 0, 0}},
+		{"scale", Scale(Base, 0.5), []time.Duration{s, 3 * s, 4 * s, 4 * s}},
+		{"clamp", Clamp(Base, 3*s, 7*s), []time.Duration{3 * s, 6 * s, 7 * s, 7 * s}},
+		{"offset", Offset(Base, s), []time.Duration{3 * s, 7 * s, 9 * s, 9 * s}},
+		{"negative offset", Offset(Base, -4*s), []time.Duration{0, 2 * s, 4 * s, 4 * s}},
+		{"sum", Sum(Base, Fixed(s)), []time.Duration{3 * s, 7 * s, 9 * s, 9 * s}},
+		{"sum empty", Sum(), []time.Duration{0, 0, 0, 0}},
+		{"nested", Clamp(Max(Scale(Base, 2), Fixed(10*s)), 0, 15*s), []time.Duration{10 * s, 12 * s, 15 * s, 15 * s}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Schedule(c, s, tt.policy, len(tt.want)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetPolicy(t *testing.T) {
+
+	d := New(&Config{Max: 8, Factor: 2}).SetDurationUnits(time.Millisecond)
+
+	if du := d.SetPolicy(Fixed(time.Hour)).Duration(); du != 0 {
+		t.Fatalf("policy applied without delay: got %s", du)
+	}
+
+	d.Incr()
+
+	if du := d.Duration(); du != time.Hour {
+		t.Fatalf("Duration with Fixed policy: got %s, want 1h", du)
+	}
+
+	if du := d.SetPolicy(Offset(Base, time.Millisecond)).Duration(); du != 3*time.Millisecond {
+		t.Fatalf("Duration with Offset policy: got %s, want 3ms", du)
+	}
+
+	if du := d.SetPolicy(nil).Duration(); du != 2*time.Millisecond {
+		t.Fatalf("Duration without policy: got %s, want 2ms", du)
+	}
+}
@@ -0,0 +1,226 @@
+// Work-queue rate limiters built on exponential backoff
+// Ограничители частоты повторной постановки элементов в очередь
+
+package ratelimit
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+type RateLimiter interface {
+	// When возвращает время, через которое элемент можно вернуть в очередь
+	When(item interface{}) time.Duration
+	// Forget прекращает отслеживание элемента
+	Forget(item interface{})
+	// NumRequeues возвращает количество повторов элемента
+	NumRequeues(item interface{}) int
+}
+
+type ItemRateLimiter struct {
+	mu            sync.Mutex
+	config        exponentialbackoff.Config
+	durationUnits time.Duration
+	maxItems      int                           // 0 - без ограничения
+	items         map[interface{}]*list.Element // элемент -> запись в lru
+	lru           *list.List                    // недавно использованные в начале
+}
+
+type itemEntry struct {
+	item     interface{}
+	delay    *exponentialbackoff.Delay
+	requeues int
+}
+
+// NewItemRateLimiter ...
+// Возвращает ограничитель с отдельной задержкой для каждого элемента.
+// При превышении maxItems вытесняются давно не использованные элементы
+func NewItemRateLimiter(c *exponentialbackoff.Config, du time.Duration, maxItems int) *ItemRateLimiter {
+
+	if maxItems < 0 {
+		maxItems = 0
+	}
+
+	return &ItemRateLimiter{
+		config:        *c,
+		durationUnits: du,
+		maxItems:      maxItems,
+		items:         make(map[interface{}]*list.Element),
+		lru:           list.New(),
+	}
+}
+
+// When ...
+// Увеличивает задержку элемента и возвращает её
+func (r *ItemRateLimiter) When(item interface{}) time.Duration {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e := r.entry(item)
+	e.requeues++
+
+	return e.delay.Incr().Duration()
+}
+
+// Forget ...
+func (r *ItemRateLimiter) Forget(item interface{}) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if el, ok := r.items[item]; ok {
+		r.lru.Remove(el)
+		delete(r.items, item)
+	}
+}
+
+// NumRequeues ...
+func (r *ItemRateLimiter) NumRequeues(item interface{}) int {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if el, ok := r.items[item]; ok {
+		return el.Value.(*itemEntry).requeues
+	}
+
+	return 0
+}
+
+// Len ...
+// Количество отслеживаемых элементов
+func (r *ItemRateLimiter) Len() int {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.lru.Len()
+}
+
+func (r *ItemRateLimiter) entry(item interface{}) *itemEntry {
+
+	if el, ok := r.items[item]; ok {
+		r.lru.MoveToFront(el)
+		return el.Value.(*itemEntry)
+	}
+
+	c := r.config
+	e := &itemEntry{
+		item:  item,
+		delay: exponentialbackoff.New(&c).SetDurationUnits(r.durationUnits),
+	}
+	r.items[item] = r.lru.PushFront(e)
+
+	for r.maxItems > 0 && r.lru.Len() > r.maxItems {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.items, oldest.Value.(*itemEntry).item)
+	}
+
+	return e
+}
+
+type BucketRateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // время пополнения одного токена
+	burst    int
+	next     time.Time // момент, когда корзина станет полной
+}
+
+// NewBucketRateLimiter ...
+// Возвращает общий для всех элементов ограничитель
+// "token bucket" с частотой qps и ёмкостью burst
+func NewBucketRateLimiter(qps float64, burst int) *BucketRateLimiter {
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	var interval time.Duration
+	if qps > 0 {
+		interval = time.Duration(float64(time.Second) / qps)
+	}
+
+	return &BucketRateLimiter{
+		interval: interval,
+		burst:    burst,
+	}
+}
+
+// When ...
+// Резервирует токен и возвращает время ожидания до него
+func (b *BucketRateLimiter) When(_ interface{}) time.Duration {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+
+	// корзина не может быть полнее burst токенов
+	full := now.Add(-time.Duration(b.burst-1) * b.interval)
+	if b.next.Before(full) {
+		b.next = full
+	}
+
+	wait := b.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+
+	b.next = b.next.Add(b.interval)
+
+	return wait
+}
+
+// Forget ...
+func (b *BucketRateLimiter) Forget(_ interface{}) {}
+
+// NumRequeues ...
+func (b *BucketRateLimiter) NumRequeues(_ interface{}) int {
+	return 0
+}
+
+type maxOf []RateLimiter
+
+// MaxOf ...
+// Объединяет ограничители: When возвращает наибольшую задержку,
+// NumRequeues - наибольшее количество повторов
+func MaxOf(limiters ...RateLimiter) RateLimiter {
+	return maxOf(limiters)
+}
+
+func (m maxOf) When(item interface{}) time.Duration {
+
+	var max time.Duration
+
+	for _, l := range m {
+		if d := l.When(item); d > max {
+			max = d
+		}
+	}
+
+	return max
+}
+
+func (m maxOf) Forget(item interface{}) {
+	for _, l := range m {
+		l.Forget(item)
+	}
+}
+
+func (m maxOf) NumRequeues(item interface{}) int {
+
+	var max int
+
+	for _, l := range m {
+		if n := l.NumRequeues(item); n > max {
+			max = n
+		}
+	}
+
+	return max
+}
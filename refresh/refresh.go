@@ -0,0 +1,153 @@
+// Periodically refreshed value served stale while refresh backs off
+// Периодически обновляемое значение: при ошибке обновления отдаётся
+// последнее удачное, а повтор откладывается экспоненциальной задержкой
+
+package refresh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+var (
+	ErrNoValue = errors.New("refresh: no value loaded yet")
+	ErrStale   = errors.New("refresh: value is stale")
+)
+
+// Loader ...
+// Функция загрузки значения
+type Loader func(ctx context.Context) (interface{}, error)
+
+type Refresher struct {
+	loader   Loader
+	delay    *exponentialbackoff.Delay
+	interval time.Duration
+	maxStale time.Duration // 0 - без ограничения
+
+	mu       sync.RWMutex
+	value    interface{}
+	loaded   bool
+	loadedAt time.Time
+	lastErr  error
+}
+
+// New ...
+// Возвращает объект, обновляющий значение каждые interval.
+// После ошибки следующая попытка выполняется через задержку d.
+// Если значение старше maxStale, Get возвращает ошибку.
+// Неположительный interval заменяется минутой
+func New(loader Loader, d *exponentialbackoff.Delay, interval, maxStale time.Duration) *Refresher {
+
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &Refresher{
+		loader:   loader,
+		delay:    d,
+		interval: interval,
+		maxStale: maxStale,
+	}
+}
+
+// Run ...
+// Обновляет значение до отмены контекста
+func (r *Refresher) Run(ctx context.Context) {
+
+	for {
+
+		wait := r.interval
+		if r.Refresh(ctx) != nil {
+			if du := r.delay.Duration(); du > 0 {
+				wait = du
+			}
+		}
+
+		t := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Refresh ...
+// Загружает значение. При ошибке прежнее значение сохраняется,
+// а задержка увеличивается
+func (r *Refresher) Refresh(ctx context.Context) error {
+
+	v, err := r.loader(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastErr = err
+
+	if err != nil {
+		r.delay.Incr()
+		return err
+	}
+
+	r.delay.Reset()
+	r.value = v
+	r.loaded = true
+	r.loadedAt = time.Now()
+
+	return nil
+}
+
+// Get ...
+// Возвращает последнее удачно загруженное значение
+func (r *Refresher) Get() (interface{}, error) {
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.loaded {
+		if r.lastErr != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNoValue, r.lastErr)
+		}
+		return nil, ErrNoValue
+	}
+
+	if age := time.Since(r.loadedAt); r.maxStale > 0 && age > r.maxStale {
+		if r.lastErr != nil {
+			return nil, fmt.Errorf("%w: age %s: %v", ErrStale, age.Round(time.Millisecond), r.lastErr)
+		}
+		return nil, fmt.Errorf("%w: age %s", ErrStale, age.Round(time.Millisecond))
+	}
+
+	return r.value, nil
+}
+
+// Age ...
+// Возраст значения, 0 - значение не загружено
+func (r *Refresher) Age() time.Duration {
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.loaded {
+		return 0
+	}
+
+	return time.Since(r.loadedAt)
+}
+
+// LastError ...
+// Ошибка последней загрузки, nil - загрузка удалась
+func (r *Refresher) LastError() error {
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.lastErr
+}
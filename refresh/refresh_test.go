@@ -0,0 +1,134 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+var errLoad = errors.New("load failed")
+
+// loader возвращает номер вызова или errLoad, пока fail != 0
+type loader struct {
+	calls int32
+	fail  int32
+}
+
+func (l *loader) load(context.Context) (interface{}, error) {
+
+	n := atomic.AddInt32(&l.calls, 1)
+
+	if atomic.LoadInt32(&l.fail) != 0 {
+		return nil, errLoad
+	}
+
+	return int(n), nil
+}
+
+func newDelay() *exponentialbackoff.Delay {
+	return exponentialbackoff.New(&exponentialbackoff.Config{Max: 8, Factor: 2}).SetDurationUnits(time.Millisecond)
+}
+
+func TestServesStale(t *testing.T) {
+
+	l := &loader{fail: 1}
+	d := newDelay()
+	r := New(l.load, d, time.Hour, 0)
+	ctx := context.Background()
+
+	r.Refresh(ctx)
+
+	if _, err := r.Get(); !errors.Is(err, ErrNoValue) {
+		t.Fatalf("Get before the first load: got %v, want ErrNoValue", err)
+	}
+
+	if r.LastError() != errLoad {
+		t.Fatalf("LastError: got %v, want errLoad", r.LastError())
+	}
+
+	atomic.StoreInt32(&l.fail, 0)
+
+	if err := r.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.LastError() != nil || d.GetDelay() != 0 {
+		t.Fatalf("after success: LastError %v, delay %d", r.LastError(), d.GetDelay())
+	}
+
+	atomic.StoreInt32(&l.fail, 1)
+	r.Refresh(ctx)
+
+	v, err := r.Get()
+	if err != nil || v != 2 {
+		t.Fatalf("Get after a failed refresh: got %v, %v, want the stale value 2", v, err)
+	}
+
+	if r.LastError() != errLoad || d.GetDelay() != 2 {
+		t.Fatalf("after failure: LastError %v, delay %d", r.LastError(), d.GetDelay())
+	}
+}
+
+func TestMaxStale(t *testing.T) {
+
+	l := &loader{}
+	r := New(l.load, newDelay(), time.Hour, 20*time.Millisecond)
+	ctx := context.Background()
+
+	r.Refresh(ctx)
+	atomic.StoreInt32(&l.fail, 1)
+	r.Refresh(ctx)
+
+	if _, err := r.Get(); err != nil {
+		t.Fatalf("Get within maxStale: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	_, err := r.Get()
+	if !errors.Is(err, ErrStale) {
+		t.Fatalf("Get after maxStale: got %v, want ErrStale", err)
+	}
+
+	if err.Error() == ErrStale.Error() {
+		t.Fatalf("stale error does not mention the last load error: %v", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+
+	l := &loader{fail: 1}
+	r := New(l.load, newDelay(), 0, 0)
+
+	if r.interval <= 0 {
+		t.Fatalf("non-positive interval kept: %s", r.interval)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+
+	// Ошибки повторяются через задержку 2, 6, 8, 8... ms
+	time.Sleep(50 * time.Millisecond)
+	atomic.StoreInt32(&l.fail, 0)
+
+	<-done
+
+	calls := atomic.LoadInt32(&l.calls)
+	if calls < 3 || calls > 20 {
+		t.Fatalf("loader called %d times in 100ms", calls)
+	}
+
+	if _, err := r.Get(); err != nil {
+		t.Fatalf("Get after Run: %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package exponentialbackoff
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrWaitExceedsDeadline = errors.New("exponentialbackoff: wait exceeds context deadline")
+
+// Reservation ...
+// Разрешение на попытку, отложенное до окончания задержки
+type Reservation struct {
+	d    *Delay
+	at   Timestamp // момент, с которого попытка разрешена
+	prev Timestamp // предыдущий зарезервированный момент, для Cancel
+}
+
+// Allow ...
+// Прошла ли задержка с момента последнего Incr.
+// Если прошла, попытка считается выполненной: следующая
+// будет разрешена не раньше, чем через текущую задержку
+func (d *Delay) Allow() bool {
+
+	if !d.isInit {
+		return true
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	now := d.now()
+	at := d.next(now)
+
+	if at.After(now) || !d.rampAdmit(now) {
+		return false
+	}
+
+	d.reserved = d.stampAt(at, now, d.getClock())
+
+	return true
+}
+
+// Reserve ...
+// Резервирует попытку после окончания задержки.
+// Одновременные резервирования разносятся на величину задержки
+func (d *Delay) Reserve() *Reservation {
+
+	r := &Reservation{d: d}
+
+	if !d.isInit {
+		return r
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	now := d.now()
+
+	r.prev = d.reserved
+	r.at = d.stampAt(d.next(now), now, d.getClock())
+	d.reserved = r.at
+
+	return r
+}
+
+// Wait ...
+// Ожидает окончания задержки. Если ожидание закончится позже
+// срока контекста, сразу возвращает ErrWaitExceedsDeadline
+func (d *Delay) Wait(ctx context.Context) error {
+
+	if d.rampByFraction() {
+		return d.waitRamp(ctx)
+	}
+
+	r := d.Reserve()
+
+	wait := r.Delay()
+	if wait == 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+		r.Cancel()
+		return ErrWaitExceedsDeadline
+	}
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
+
+// next возвращает ближайший момент, когда попытка разрешена:
+// не раньше окончания задержки после Incr и не раньше, чем через
+// задержку после предыдущего резервирования. Моменты Incr и
+// резервирования отсчитываются по времени с загрузки, поэтому перевод
+// часов не меняет ожидание. Вызывается под блокировкой
+func (d *Delay) next(now time.Time) time.Time {
+
+	d.adjustClock(now)
+
+	wait := d.duration()
+
+	at := now
+	if elapsed := d.sinceFailure(now); elapsed < wait {
+		at = now.Add(wait - elapsed)
+	}
+
+	if d.reserved.IsZero() {
+		return at
+	}
+
+	reserved := now.Add(d.reserved.until(now, d.getClock()))
+
+	if min := reserved.Add(wait); at.Before(min) {
+		at = min
+	}
+
+	// При разгоне после Reset вызовы разносятся на уменьшающийся интервал
+	if spacing := d.rampSpacing(now); spacing > 0 {
+		if min := reserved.Add(spacing); at.Before(min) {
+			at = min
+		}
+	}
+
+	if at.Before(now) {
+		at = now
+	}
+
+	return at
+}
+
+// Delay ...
+// Время, оставшееся до разрешённой попытки
+func (r *Reservation) Delay() time.Duration {
+
+	if r.at.IsZero() {
+		return 0
+	}
+
+	c := r.d.getClock()
+
+	if wait := r.at.until(c.Now(), c); wait > 0 {
+		return wait
+	}
+
+	return 0
+}
+
+// DelayFrom ...
+// Время от now до разрешённой попытки по настенному времени
+func (r *Reservation) DelayFrom(now time.Time) time.Duration {
+
+	if r.at.Wall.Before(now) {
+		return 0
+	}
+
+	return r.at.Wall.Sub(now)
+}
+
+// Time ...
+// Момент, с которого попытка разрешена
+func (r *Reservation) Time() time.Time {
+	return r.at.Wall
+}
+
+// Cancel ...
+// Отменяет резервирование, если после него не было других
+// резервирований и Incr, и попытка ещё не разрешена
+func (r *Reservation) Cancel() {
+
+	if r.d == nil || !r.d.isInit {
+		return
+	}
+
+	r.d.Lock()
+	defer r.d.Unlock()
+
+	c := r.d.getClock()
+
+	if r.d.reserved == r.at && r.at.until(c.Now(), c) > 0 {
+		r.d.reserved = r.prev
+	}
+}
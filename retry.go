@@ -0,0 +1,125 @@
+package exponentialbackoff
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// NestedPolicy ...
+// Поведение цикла повторов внутри другого цикла повторов
+type NestedPolicy int
+
+const (
+	// NestedRetry - повторять независимо от внешнего цикла
+	NestedRetry NestedPolicy = iota
+	// NestedDisable - не повторять, если внешний цикл уже повторяет
+	NestedDisable
+	// NestedShare - расходовать общий с внешним циклом запас повторов
+	NestedShare
+)
+
+type retryScopeKey struct{}
+
+// RetryScope ...
+// Запас повторов цикла, сохраняемый в контексте,
+// чтобы вложенные циклы могли его обнаружить
+type RetryScope struct {
+	disabled bool
+	own      int64  // собственный запас повторов, -1 - без ограничения
+	budget   *int64 // запас, общий с внешним циклом, -1 - без ограничения
+}
+
+// EnterRetry ...
+// Начинает цикл повторов с не более чем maxAttempts попытками
+// (0 - без ограничения) и возвращает контекст с отметкой о нём.
+// Если контекст уже отмечен внешним циклом, запас определяется nested
+func EnterRetry(ctx context.Context, maxAttempts int, nested NestedPolicy) (context.Context, *RetryScope) {
+
+	own := int64(maxAttempts - 1)
+	if maxAttempts <= 0 {
+		own = -1
+	}
+
+	outer, _ := ctx.Value(retryScopeKey{}).(*RetryScope)
+
+	var s *RetryScope
+
+	switch {
+	case outer == nil || nested == NestedRetry:
+		budget := own
+		s = &RetryScope{own: -1, budget: &budget}
+	case nested == NestedDisable:
+		s = &RetryScope{disabled: true}
+	default:
+		s = &RetryScope{own: own, budget: outer.budget, disabled: outer.disabled}
+	}
+
+	return context.WithValue(ctx, retryScopeKey{}, s), s
+}
+
+// InRetry ...
+// Выполняется ли вызов внутри цикла повторов
+func InRetry(ctx context.Context) bool {
+	_, ok := ctx.Value(retryScopeKey{}).(*RetryScope)
+	return ok
+}
+
+// Next ...
+// Можно ли сделать ещё одну попытку. Расходует запас повторов
+func (s *RetryScope) Next() bool {
+
+	if s.disabled || s.own == 0 {
+		return false
+	}
+
+	for {
+
+		v := atomic.LoadInt64(s.budget)
+		if v < 0 {
+			break
+		}
+
+		if v == 0 {
+			return false
+		}
+
+		if atomic.CompareAndSwapInt64(s.budget, v, v-1) {
+			break
+		}
+	}
+
+	if s.own > 0 {
+		s.own--
+	}
+
+	return true
+}
+
+// Retry ...
+// Вызывает fn, пока она не завершится успешно или постоянной ошибкой
+// (Permanent), повторяя после задержки d. maxAttempts ограничивает
+// количество попыток, 0 - без ограничения; nested задаёт поведение
+// внутри другого цикла повторов. Возвращает последнюю ошибку fn
+func Retry(ctx context.Context, d *Delay, maxAttempts int, nested NestedPolicy, fn func(ctx context.Context) error) error {
+
+	ctx, s := EnterRetry(ctx, maxAttempts, nested)
+
+	for {
+
+		err := fn(ctx)
+		if err == nil {
+			d.Decr()
+			return nil
+		}
+
+		if IsPermanent(err) || !s.Next() {
+			return err
+		}
+
+		d.Incr()
+
+		if _, berr, _ := d.Backoff(ctx); berr != nil {
+			return err
+		}
+	}
+}
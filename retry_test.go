@@ -0,0 +1,89 @@
+package exponentialbackoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errFailed = errors.New("failed")
+
+func newRetryDelay() *Delay {
+	return New(&Config{Max: 4, Factor: 2}).SetDurationUnits(time.Microsecond)
+}
+
+func TestRetryNested(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		outer  int
+		inner  int
+		nested NestedPolicy
+		want   int
+	}{
+		{"retry", 3, 3, NestedRetry, 9},
+		{"disable", 3, 3, NestedDisable, 3},
+		{"share", 3, 3, NestedShare, 3},
+		// Общий запас расходуется и внутренним, и внешним циклом
+		{"share smaller inner", 5, 2, NestedShare, 5},
+		{"share unlimited inner", 3, 0, NestedShare, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			calls := 0
+
+			err := Retry(context.Background(), newRetryDelay(), tt.outer, NestedRetry, func(ctx context.Context) error {
+				return Retry(ctx, newRetryDelay(), tt.inner, tt.nested, func(context.Context) error {
+					calls++
+					return errFailed
+				})
+			})
+
+			if err != errFailed {
+				t.Fatalf("got %v, want %v", err, errFailed)
+			}
+
+			if calls != tt.want {
+				t.Fatalf("got %d attempts, want %d", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPermanent(t *testing.T) {
+
+	calls := 0
+
+	err := Retry(context.Background(), newRetryDelay(), 0, NestedRetry, func(context.Context) error {
+		calls++
+		if calls == 2 {
+			return Permanent(errFailed)
+		}
+		return errFailed
+	})
+
+	if !IsPermanent(err) || !errors.Is(err, errFailed) {
+		t.Fatalf("got %v, want permanent %v", err, errFailed)
+	}
+
+	if calls != 2 {
+		t.Fatalf("got %d attempts, want 2", calls)
+	}
+}
+
+func TestInRetry(t *testing.T) {
+
+	if InRetry(context.Background()) {
+		t.Fatal("InRetry outside of Retry")
+	}
+
+	Retry(context.Background(), newRetryDelay(), 1, NestedRetry, func(ctx context.Context) error {
+		if !InRetry(ctx) {
+			t.Fatal("InRetry inside of Retry returned false")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,165 @@
+package exponentialbackoff
+
+import (
+	"context"
+	"time"
+)
+
+// SlowStart ...
+// Настройки плавного разгона после Reset: если до Reset задержка была
+// не меньше Threshold, в течение Window после него Allow и Wait
+// пропускают вызовы постепенно
+type SlowStart struct {
+	Window    time.Duration // Длительность разгона
+	Threshold int           // Минимальное значение задержки перед Reset, включающее разгон
+	// Начальный минимальный интервал между вызовами, линейно уменьшающийся
+	// до нуля к концу разгона. 0 - вместо интервала пропускается доля вызовов,
+	// линейно растущая от 0 до 1
+	Spacing time.Duration
+}
+
+// SetSlowStart ...
+// Установить плавный разгон после Reset, nil - отключить
+func (d *Delay) SetSlowStart(s *SlowStart) *Delay {
+
+	if !d.isInit {
+		return d
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.slowStart = s
+	d.recoveredAt = time.Time{}
+
+	return d
+}
+
+// Recovering ...
+// Идёт ли разгон после Reset
+func (d *Delay) Recovering() bool {
+
+	if !d.isInit {
+		return false
+	}
+
+	d.RLock()
+	defer d.RUnlock()
+
+	_, ok := d.rampProgress(d.now())
+
+	return ok
+}
+
+// startRamp начинает разгон, если задержка была достаточно большой.
+// Вызывается под блокировкой перед сбросом задержки
+func (d *Delay) startRamp() {
+
+	s := d.slowStart
+	if s == nil || s.Window <= 0 || d.i <= 0 || d.i < s.Threshold {
+		return
+	}
+
+	d.recoveredAt = d.now()
+	d.credit = 0
+}
+
+// rampProgress возвращает долю пройденного разгона от 0 до 1
+// и признак того, что разгон идёт. Вызывается под блокировкой
+func (d *Delay) rampProgress(now time.Time) (float64, bool) {
+
+	s := d.slowStart
+	if s == nil || s.Window <= 0 || d.recoveredAt.IsZero() {
+		return 1, false
+	}
+
+	elapsed := now.Sub(d.recoveredAt)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	if elapsed >= s.Window {
+		return 1, false
+	}
+
+	return float64(elapsed) / float64(s.Window), true
+}
+
+// rampSpacing возвращает текущий минимальный интервал между вызовами.
+// Вызывается под блокировкой
+func (d *Delay) rampSpacing(now time.Time) time.Duration {
+
+	progress, ok := d.rampProgress(now)
+	if !ok || d.slowStart.Spacing <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(d.slowStart.Spacing) * (1 - progress))
+}
+
+// rampAdmit решает, пропустить ли вызов в режиме доли вызовов.
+// Вызывается под блокировкой
+func (d *Delay) rampAdmit(now time.Time) bool {
+
+	progress, ok := d.rampProgress(now)
+	if !ok || d.slowStart.Spacing > 0 {
+		return true
+	}
+
+	d.credit += progress
+	if d.credit < 1 {
+		return false
+	}
+
+	d.credit--
+
+	return true
+}
+
+// rampByFraction сообщает, идёт ли разгон в режиме доли вызовов
+func (d *Delay) rampByFraction() bool {
+
+	if !d.isInit {
+		return false
+	}
+
+	d.RLock()
+	defer d.RUnlock()
+
+	_, ok := d.rampProgress(d.now())
+
+	return ok && d.slowStart.Spacing <= 0
+}
+
+// waitRamp ожидает разрешения Allow в режиме доли вызовов,
+// опрашивая его с интервалом в 1/20 длительности разгона, но не чаще
+// раза в миллисекунду
+func (d *Delay) waitRamp(ctx context.Context) error {
+
+	for !d.Allow() {
+
+		t := time.NewTimer(d.rampPoll())
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (d *Delay) rampPoll() time.Duration {
+
+	d.RLock()
+	defer d.RUnlock()
+
+	poll := time.Millisecond
+	if d.slowStart != nil && d.slowStart.Window/20 > poll {
+		poll = d.slowStart.Window / 20
+	}
+
+	return poll
+}
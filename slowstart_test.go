@@ -0,0 +1,166 @@
+package exponentialbackoff
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newRampDelay(c Clock, s *SlowStart) *Delay {
+	// Задержка 2 перед Reset включает разгон
+	return newClockDelay(c).SetSlowStart(s).Incr().Reset()
+}
+
+func TestSlowStartThreshold(t *testing.T) {
+
+	c := newFakeClock("boot-1")
+
+	d := newClockDelay(c).SetSlowStart(&SlowStart{Window: 10 * time.Second, Threshold: 6}).Incr().Reset()
+	if d.Recovering() {
+		t.Fatal("ramp started below the threshold")
+	}
+
+	d = newRampDelay(c, &SlowStart{Window: 10 * time.Second, Threshold: 2})
+	if !d.Recovering() {
+		t.Fatal("ramp not started at the threshold")
+	}
+
+	c.advance(10 * time.Second)
+	if d.Recovering() {
+		t.Fatal("ramp still running after the window")
+	}
+}
+
+func TestSlowStartSpacing(t *testing.T) {
+
+	c := newFakeClock("boot-1")
+	d := newRampDelay(c, &SlowStart{Window: 10 * time.Second, Threshold: 2, Spacing: time.Second})
+
+	steps := []struct {
+		advance time.Duration
+		want    time.Duration
+	}{
+		{0, 0},
+		{0, time.Second}, // интервал в начале разгона
+		{5 * time.Second, 0},
+		{0, 500 * time.Millisecond}, // середина разгона: половина интервала
+		{5 * time.Second, 0},
+		{0, 0}, // разгон закончился
+	}
+
+	for i, s := range steps {
+
+		c.advance(s.advance)
+
+		if wait := d.Reserve().Delay(); wait != s.want {
+			t.Fatalf("step %d: got %s, want %s", i, wait, s.want)
+		}
+	}
+}
+
+func TestSlowStartFraction(t *testing.T) {
+
+	c := newFakeClock("boot-1")
+	d := newRampDelay(c, &SlowStart{Window: 10 * time.Second, Threshold: 2})
+
+	steps := []struct {
+		advance time.Duration
+		want    int // пропущено вызовов из 100
+	}{
+		{0, 0},
+		{2500 * time.Millisecond, 25},
+		{2500 * time.Millisecond, 50},
+		{5 * time.Second, 100},
+	}
+
+	for i, s := range steps {
+
+		c.advance(s.advance)
+
+		allowed := 0
+		for j := 0; j < 100; j++ {
+			if d.Allow() {
+				allowed++
+			}
+		}
+
+		if allowed != s.want {
+			t.Fatalf("step %d: allowed %d of 100, want %d", i, allowed, s.want)
+		}
+	}
+}
+
+func TestSlowStartWait(t *testing.T) {
+
+	d := New(&Config{Max: 60, Factor: 2}).
+		SetSlowStart(&SlowStart{Window: 100 * time.Millisecond, Threshold: 2}).
+		Incr().
+		Reset()
+
+	start := time.Now()
+
+	if err := d.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Wait took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	d.SetSlowStart(&SlowStart{Window: time.Hour, Threshold: 2}).Incr().Reset()
+
+	if err := d.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Wait at the start of a long ramp: got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestSlowStartWaitConcurrentDisable(t *testing.T) {
+
+	const window = time.Second
+
+	d := New(&Config{Max: 60, Factor: 2}).
+		SetSlowStart(&SlowStart{Window: window, Threshold: 2}).
+		Incr().
+		Reset()
+
+	var wg sync.WaitGroup
+
+	start := time.Now()
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.Wait(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	d.SetSlowStart(nil)
+
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed >= window/2 {
+		t.Fatalf("Wait returned after %s, disabling the ramp did not release it", elapsed)
+	}
+}
+
+func TestSlowStartShortWindow(t *testing.T) {
+
+	d := New(&Config{Max: 60, Factor: 2}).
+		SetSlowStart(&SlowStart{Window: 10 * time.Nanosecond, Threshold: 2})
+
+	if poll := d.rampPoll(); poll < time.Millisecond {
+		t.Fatalf("poll interval %s is shorter than 1ms", poll)
+	}
+
+	if err := d.Incr().Reset().Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,139 @@
+// Exponential backoff for database/sql connections
+// Экспоненциальная задержка при установке соединений database/sql
+
+package sqlbackoff
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+type Connector struct {
+	mu        sync.Mutex
+	connector driver.Connector
+	delay     *exponentialbackoff.Delay
+	until     time.Time // до этого момента новые соединения не устанавливаются
+}
+
+// NewConnector ...
+// Оборачивает driver.Connector: после неудачного Connect
+// последующие попытки сразу завершаются с driver.ErrBadConn,
+// пока не истечёт текущая задержка d
+func NewConnector(c driver.Connector, d *exponentialbackoff.Delay) *Connector {
+	return &Connector{
+		connector: c,
+		delay:     d,
+	}
+}
+
+// NewDriverConnector ...
+// Оборачивает драйвер, не реализующий driver.DriverContext
+func NewDriverConnector(drv driver.Driver, dsn string, d *exponentialbackoff.Delay) *Connector {
+	return NewConnector(&dsnConnector{driver: drv, dsn: dsn}, d)
+}
+
+// OpenDB ...
+// Возвращает пул соединений с задержкой установки соединений
+func OpenDB(c driver.Connector, d *exponentialbackoff.Delay) *sql.DB {
+	return sql.OpenDB(NewConnector(c, d))
+}
+
+// Connect ...
+// Реализует driver.Connector
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+
+	if c.inBackoff() {
+		return nil, driver.ErrBadConn
+	}
+
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		c.fail()
+		return nil, err
+	}
+
+	c.succeed()
+
+	return conn, nil
+}
+
+// Driver ...
+// Реализует driver.Connector
+func (c *Connector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// Close ...
+// Закрывает обёрнутый коннектор, если он реализует io.Closer
+func (c *Connector) Close() error {
+
+	if cl, ok := c.connector.(io.Closer); ok {
+		return cl.Close()
+	}
+
+	return nil
+}
+
+// Until ...
+// Момент, до которого соединения не устанавливаются
+func (c *Connector) Until() time.Time {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.until
+}
+
+func (c *Connector) inBackoff() bool {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return time.Now().Before(c.until)
+}
+
+// succeed сбрасывает задержку и окно: соединение, начатое до
+// неудачи другого соединения, показывает, что база доступна
+func (c *Connector) succeed() {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.until = time.Time{}
+	c.delay.Reset()
+}
+
+// fail начинает новое окно задержки. Неудачи соединений, начатых
+// до истечения текущего окна, задержку не увеличивают: пул может
+// открывать несколько соединений одновременно
+func (c *Connector) fail() {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(c.until) {
+		return
+	}
+
+	c.until = now.Add(c.delay.Incr().Duration())
+}
+
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c *dsnConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
@@ -0,0 +1,151 @@
+package sqlbackoff
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+var errDown = errors.New("database is down")
+
+type fakeDriver struct {
+	down  int32
+	dials int32
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+
+	atomic.AddInt32(&d.dials, 1)
+
+	if atomic.LoadInt32(&d.down) != 0 {
+		return nil, errDown
+	}
+
+	return fakeConn{}, nil
+}
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not implemented") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not implemented") }
+
+func newTestConnector(drv *fakeDriver) (*Connector, *exponentialbackoff.Delay) {
+
+	d := exponentialbackoff.New(&exponentialbackoff.Config{Max: 8, Factor: 2}).
+		SetDurationUnits(50 * time.Millisecond)
+
+	return NewDriverConnector(drv, "", d), d
+}
+
+func TestConnectBackoffWindow(t *testing.T) {
+
+	drv := &fakeDriver{down: 1}
+	c, d := newTestConnector(drv)
+	ctx := context.Background()
+
+	if _, err := c.Connect(ctx); err != errDown {
+		t.Fatalf("first Connect: got %v, want %v", err, errDown)
+	}
+
+	if d.GetDelay() != 2 {
+		t.Fatalf("delay after failure: got %d, want 2", d.GetDelay())
+	}
+
+	atomic.StoreInt32(&drv.down, 0)
+
+	if _, err := c.Connect(ctx); err != driver.ErrBadConn {
+		t.Fatalf("Connect during backoff: got %v, want driver.ErrBadConn", err)
+	}
+
+	if n := atomic.LoadInt32(&drv.dials); n != 1 {
+		t.Fatalf("driver dialled during backoff: %d dials", n)
+	}
+
+	time.Sleep(time.Until(c.Until()) + 10*time.Millisecond)
+
+	conn, err := c.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect after backoff: %v", err)
+	}
+	conn.Close()
+
+	if d.GetDelay() != 0 {
+		t.Fatalf("delay after success: got %d, want 0", d.GetDelay())
+	}
+}
+
+func TestConcurrentFailuresIncrOnce(t *testing.T) {
+
+	drv := &fakeDriver{down: 1}
+	c, d := newTestConnector(drv)
+
+	// Все соединения начаты до первой неудачи
+	c.connector = &slowConnector{Connector: c.connector, wait: 20 * time.Millisecond}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Connect(context.Background())
+		}()
+	}
+
+	wg.Wait()
+
+	if d.GetDelay() != 2 {
+		t.Fatalf("delay after concurrent failures: got %d, want 2", d.GetDelay())
+	}
+
+	if s := d.Stats(); s.Incr != 1 {
+		t.Fatalf("Incr calls: got %d, want 1", s.Incr)
+	}
+}
+
+type slowConnector struct {
+	driver.Connector
+	wait time.Duration
+}
+
+func (c *slowConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	time.Sleep(c.wait)
+	return c.Connector.Connect(ctx)
+}
+
+func TestSuccessClearsWindow(t *testing.T) {
+
+	drv := &fakeDriver{down: 1}
+	c, d := newTestConnector(drv)
+	ctx := context.Background()
+
+	c.Connect(ctx)
+	atomic.StoreInt32(&drv.down, 0)
+
+	// Соединение, начатое до неудачи, завершилось успешно
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	c.succeed()
+
+	if !c.Until().IsZero() {
+		t.Fatalf("window left open after success: until %s", c.Until())
+	}
+
+	if d.GetDelay() != 0 {
+		t.Fatalf("delay after success: got %d, want 0", d.GetDelay())
+	}
+
+	if _, err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect after success: %v", err)
+	}
+}
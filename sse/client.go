@@ -0,0 +1,211 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+// ErrNoContent возвращается, когда сервер ответил 204 и просит не переподключаться
+var ErrNoContent = errors.New("sse: server responded 204 No Content")
+
+type Client struct {
+	url         string
+	delay       *exponentialbackoff.Delay
+	http        *http.Client
+	header      http.Header
+	healthy     time.Duration
+	lastEventID string
+	onError     func(error)
+	nested      exponentialbackoff.NestedPolicy
+}
+
+// NewClient ...
+// Возвращает клиента потока url. Первое переподключение выполняется
+// через единицу времени задержки d, следующие - через растущую задержку d.
+// Поле retry сервера задаёт единицу времени задержки. Задержка d
+// принадлежит клиенту и не должна использоваться одновременно в другом месте
+func NewClient(url string, d *exponentialbackoff.Delay) *Client {
+	return &Client{
+		url:     url,
+		delay:   d,
+		http:    http.DefaultClient,
+		header:  make(http.Header),
+		healthy: time.Minute,
+	}
+}
+
+// SetHTTPClient ...
+func (c *Client) SetHTTPClient(hc *http.Client) *Client {
+	c.http = hc
+	return c
+}
+
+// SetHeader ...
+// Установить заголовок запроса
+func (c *Client) SetHeader(key, value string) *Client {
+	c.header.Set(key, value)
+	return c
+}
+
+// SetHealthy ...
+// Установить длительность потока, после которой он считается
+// здоровым и задержка сбрасывается
+func (c *Client) SetHealthy(du time.Duration) *Client {
+	c.healthy = du
+	return c
+}
+
+// SetLastEventID ...
+// Установить идентификатор, с которого продолжить поток
+func (c *Client) SetLastEventID(id string) *Client {
+	c.lastEventID = id
+	return c
+}
+
+// OnError ...
+// Установить обработчик ошибок соединения
+func (c *Client) OnError(fn func(error)) *Client {
+	c.onError = fn
+	return c
+}
+
+// SetNested ...
+// Установить поведение внутри другого цикла повторов,
+// например exponentialbackoff.NestedDisable
+func (c *Client) SetNested(p exponentialbackoff.NestedPolicy) *Client {
+	c.nested = p
+	return c
+}
+
+// LastEventID ...
+// Последний полученный идентификатор события
+func (c *Client) LastEventID() string {
+	return c.lastEventID
+}
+
+// Subscribe ...
+// Читает поток и вызывает handler для каждого события, переподключаясь
+// с задержкой. Работает до отмены контекста, ответа 204 или, внутри
+// другого цикла повторов, исчерпания его запаса повторов (см. SetNested).
+// В последнем случае возвращает последнюю ошибку потока или io.EOF
+func (c *Client) Subscribe(ctx context.Context, handler func(Event)) error {
+
+	ctx, scope := exponentialbackoff.EnterRetry(ctx, 0, c.nested)
+
+	for {
+
+		start := time.Now()
+
+		err := c.stream(ctx, handler)
+		if errors.Is(err, ErrNoContent) {
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err != nil && c.onError != nil {
+			c.onError(err)
+		}
+
+		if time.Since(start) >= c.healthy {
+			c.delay.Reset()
+		}
+
+		if !scope.Next() {
+			if err == nil {
+				err = io.EOF
+			}
+			return err
+		}
+
+		if err := c.backoff(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// backoff ожидает перед переподключением. Если задержки нет, она
+// устанавливается в 1, так что ожидание равно единице времени задержки,
+// то есть retry сервера. После ожидания задержка увеличивается
+func (c *Client) backoff(ctx context.Context) error {
+
+	if !c.delay.IssetDelay() {
+		c.delay.SetDelay(1)
+	}
+
+	if _, err, _ := c.delay.Backoff(ctx); err != nil {
+		return err
+	}
+
+	c.delay.Incr()
+
+	return nil
+}
+
+func (c *Client) stream(ctx context.Context, handler func(Event)) error {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	if c.lastEventID != "" {
+		req.Header.Set("Last-Event-ID", c.lastEventID)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return ErrNoContent
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sse: %s: %s", c.url, resp.Status)
+	}
+
+	if mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mt != "text/event-stream" {
+		return fmt.Errorf("sse: %s: unexpected content type %q", c.url, mt)
+	}
+
+	r := NewReader(resp.Body)
+	r.lastID = c.lastEventID
+
+	for {
+
+		ev, err := r.Next()
+
+		if retry := r.Retry(); retry > 0 {
+			c.delay.SetDurationUnits(retry)
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		c.lastEventID = ev.ID
+		handler(ev)
+	}
+}
@@ -0,0 +1,111 @@
+package sse
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+type connection struct {
+	start, end  time.Time
+	lastEventID string
+}
+
+func TestSubscribeReconnect(t *testing.T) {
+
+	const retry = 50 * time.Millisecond
+
+	var (
+		mu    sync.Mutex
+		conns []connection
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		mu.Lock()
+		n := len(conns)
+		conns = append(conns, connection{start: time.Now(), lastEventID: r.Header.Get("Last-Event-ID")})
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		if n == 0 {
+			fmt.Fprintf(w, "retry: %d\n\n", retry/time.Millisecond)
+		}
+		fmt.Fprintf(w, "id: %d\ndata: event\n\n", n+1)
+		w.(http.Flusher).Flush()
+
+		switch n {
+		case 3:
+			// Поток дольше SetHealthy: задержка сбрасывается
+			time.Sleep(150 * time.Millisecond)
+		case 4:
+			cancel()
+		}
+
+		mu.Lock()
+		conns[n].end = time.Now()
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	d := exponentialbackoff.New(&exponentialbackoff.Config{Max: 60, Factor: 2})
+
+	err := NewClient(srv.URL, d).SetHealthy(100*time.Millisecond).Subscribe(ctx, func(Event) {})
+	if err != context.Canceled {
+		t.Fatalf("Subscribe: got %v, want context.Canceled", err)
+	}
+
+	if s := d.Stats(); s.Backoffs != 4 {
+		t.Errorf("backoffs: got %d, want 4, every wait goes through Backoff", s.Backoffs)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(conns) != 5 {
+		t.Fatalf("got %d connections, want 5", len(conns))
+	}
+
+	for i := 1; i < len(conns); i++ {
+		if want := fmt.Sprint(i); conns[i].lastEventID != want {
+			t.Errorf("connection %d: Last-Event-ID %q, want %q", i, conns[i].lastEventID, want)
+		}
+	}
+
+	// Задержка 1, затем 1*2+2 = 4 и 4*2+2 = 10; после здорового потока снова 1
+	wants := []time.Duration{retry, 4 * retry, 10 * retry, retry}
+
+	for i, want := range wants {
+		gap := conns[i+1].start.Sub(conns[i].end)
+		if gap < want-5*time.Millisecond || gap > want+want/2 {
+			t.Errorf("gap before connection %d: got %s, want about %s", i+1, gap, want)
+		}
+	}
+}
+
+func TestBackoffCancelled(t *testing.T) {
+
+	d := exponentialbackoff.New(&exponentialbackoff.Config{Max: 60, Factor: 2}).Incr()
+	c := NewClient("http://localhost", d)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := c.backoff(ctx); err != context.Canceled {
+		t.Fatalf("backoff: got %v, want context.Canceled", err)
+	}
+
+	if d.GetDelay() != 2 {
+		t.Fatalf("delay after cancelled backoff: got %d, want 2", d.GetDelay())
+	}
+}
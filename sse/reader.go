@@ -0,0 +1,147 @@
+// Server-Sent Events client with exponential reconnect backoff
+// Клиент Server-Sent Events с переподключением и экспоненциальной задержкой
+
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event ...
+// Событие потока
+type Event struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+type Reader struct {
+	scanner *bufio.Scanner
+	lastID  string
+	retry   time.Duration
+}
+
+// NewReader ...
+// Возвращает разборщик потока событий
+func NewReader(r io.Reader) *Reader {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), 1<<20)
+	scanner.Split(scanLines)
+
+	return &Reader{scanner: scanner}
+}
+
+// Next ...
+// Возвращает очередное событие. В конце потока возвращает io.EOF.
+// Поле ID наследуется от предыдущих событий, как требует спецификация
+func (r *Reader) Next() (Event, error) {
+
+	var (
+		ev   Event
+		data strings.Builder
+		has  bool // было ли поле data
+	)
+
+	for r.scanner.Scan() {
+
+		line := r.scanner.Text()
+
+		if line == "" {
+
+			if !has {
+				ev = Event{}
+				continue
+			}
+
+			ev.ID = r.lastID
+			ev.Data = strings.TrimSuffix(data.String(), "\n")
+			if ev.Event == "" {
+				ev.Event = "message"
+			}
+
+			return ev, nil
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+
+		switch field {
+		case "event":
+			ev.Event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			has = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				r.lastID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				r.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+
+	return Event{}, io.EOF
+}
+
+// LastEventID ...
+// Последний полученный идентификатор события
+func (r *Reader) LastEventID() string {
+	return r.lastID
+}
+
+// Retry ...
+// Последнее значение поля retry, 0 - не задано
+func (r *Reader) Retry() time.Duration {
+	return r.retry
+}
+
+// scanLines разбивает поток на строки, оканчивающиеся на \r\n, \n или \r
+func scanLines(data []byte, atEOF bool) (int, []byte, error) {
+
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+
+		// \r в конце буфера: следующий байт может быть \n
+		if i+1 == len(data) && !atEOF {
+			return 0, nil, nil
+		}
+
+		if i+1 < len(data) && data[i+1] == '\n' {
+			return i + 2, data[:i], nil
+		}
+
+		return i + 1, data[:i], nil
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
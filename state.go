@@ -0,0 +1,188 @@
+package exponentialbackoff
+
+import "time"
+
+// Timestamp ...
+// Момент времени, пригодный для сохранения: кроме настенного времени
+// хранит время с загрузки системы, не подверженное переводу часов
+type Timestamp struct {
+	Wall   time.Time     `json:"wall"`
+	Boot   time.Duration `json:"boot,omitempty"`    // Время с загрузки системы, 0 - недоступно
+	BootID string        `json:"boot_id,omitempty"` // Идентификатор загрузки системы
+}
+
+// State ...
+// Сохраняемое состояние задержки
+type State struct {
+	Delay    int       `json:"delay"`
+	FailedAt Timestamp `json:"failed_at"` // Время последнего Incr
+}
+
+// NewTimestamp ...
+// Текущий момент по часам c
+func NewTimestamp(c Clock) Timestamp {
+
+	ts := Timestamp{Wall: c.Now()}
+
+	if since, id, ok := c.SinceBoot(); ok {
+		ts.Boot = since
+		ts.BootID = id
+	}
+
+	return ts
+}
+
+// IsZero ...
+func (ts Timestamp) IsZero() bool {
+	return ts.Wall.IsZero()
+}
+
+// Elapsed ...
+// Время, прошедшее с момента ts по часам c. В пределах одной загрузки
+// системы считается по времени с загрузки, иначе по настенному времени.
+// Если часы переведены назад, возвращает 0
+func (ts Timestamp) Elapsed(c Clock) time.Duration {
+	return ts.elapsed(c.Now(), c)
+}
+
+func (ts Timestamp) elapsed(now time.Time, c Clock) time.Duration {
+
+	if ts.BootID != "" {
+		if since, id, ok := c.SinceBoot(); ok && id == ts.BootID && since >= ts.Boot {
+			return since - ts.Boot
+		}
+	}
+
+	e := now.Sub(ts.Wall)
+	if e < 0 {
+		return 0
+	}
+
+	return e
+}
+
+// State ...
+// Возвращает состояние для сохранения
+func (d *Delay) State() State {
+
+	d.RLock()
+	defer d.RUnlock()
+
+	return State{
+		Delay:    d.i,
+		FailedAt: d.failedAt,
+	}
+}
+
+// Restore ...
+// Восстанавливает сохранённое состояние. Значение задержки
+// ограничивается максимумом, время последнего Incr - текущим моментом
+func (d *Delay) Restore(s State) *Delay {
+
+	if !d.isInit {
+		return d
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.i = s.Delay
+	if d.i < 0 {
+		d.i = 0
+	}
+	if d.i > d.max {
+		d.i = d.max
+	}
+
+	now := d.now()
+
+	d.reserved = Timestamp{}
+	d.failedAt = Timestamp{}
+	d.seen = now
+
+	if !s.FailedAt.IsZero() {
+		// Пересчёт в текущие часы: сохраняется только прошедшее время
+		c := d.getClock()
+		elapsed := s.FailedAt.elapsed(now, c)
+		d.failedAt = d.stampAt(now.Add(-elapsed), now, c)
+	}
+
+	return d
+}
+
+// stampAt возвращает момент at по часам c, now - текущий момент.
+// Время с загрузки вычисляется смещением от текущего
+func (d *Delay) stampAt(at, now time.Time, c Clock) Timestamp {
+
+	ts := Timestamp{Wall: at}
+
+	if since, id, ok := c.SinceBoot(); ok {
+		if boot := since + at.Sub(now); boot >= 0 {
+			ts.Boot = boot
+			ts.BootID = id
+		}
+	}
+
+	return ts
+}
+
+// until возвращает время от now до момента ts, отрицательное для прошедших
+// моментов. В пределах одной загрузки считается по времени с загрузки
+func (ts Timestamp) until(now time.Time, c Clock) time.Duration {
+
+	if ts.BootID != "" {
+		if since, id, ok := c.SinceBoot(); ok && id == ts.BootID {
+			return ts.Boot - since
+		}
+	}
+
+	return ts.Wall.Sub(now)
+}
+
+// adjustClock сдвигает вместе с часами моменты, сохранённые без времени
+// с загрузки, если часы переведены назад: время до них не меняется, а время,
+// прошедшее с последнего вызова до перевода, не учитывается.
+// Перевод вперёд без времени с загрузки не обнаруживается.
+// Вызывается под блокировкой
+func (d *Delay) adjustClock(now time.Time) {
+
+	if !d.seen.IsZero() && now.Before(d.seen) {
+
+		shift := now.Sub(d.seen)
+
+		if !d.failedAt.IsZero() && d.failedAt.BootID == "" {
+			d.failedAt.Wall = d.failedAt.Wall.Add(shift)
+		}
+
+		if !d.reserved.IsZero() && d.reserved.BootID == "" {
+			d.reserved.Wall = d.reserved.Wall.Add(shift)
+		}
+
+		if !d.recoveredAt.IsZero() {
+			d.recoveredAt = d.recoveredAt.Add(shift)
+		}
+	}
+
+	d.seen = now
+}
+
+// sinceFailure возвращает время с последнего Incr, не меньше 0.
+// Если момент Incr без времени с загрузки оказался в будущем,
+// он переносится на now, а резервирования сбрасываются.
+// Вызывается под блокировкой
+func (d *Delay) sinceFailure(now time.Time) time.Duration {
+
+	if d.failedAt.IsZero() {
+		return 1<<63 - 1
+	}
+
+	c := d.getClock()
+
+	if d.failedAt.BootID == "" && now.Before(d.failedAt.Wall) {
+		d.failedAt = d.stampAt(now, now, c)
+		d.reserved = Timestamp{}
+		return 0
+	}
+
+	return d.failedAt.elapsed(now, c)
+}
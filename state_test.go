@@ -0,0 +1,273 @@
+package exponentialbackoff
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock - часы, настенное время которых переводится отдельно
+// от времени с загрузки. Пустой id - время с загрузки недоступно
+type fakeClock struct {
+	wall time.Time
+	boot time.Duration
+	id   string
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.wall
+}
+
+func (c *fakeClock) SinceBoot() (time.Duration, string, bool) {
+	return c.boot, c.id, c.id != ""
+}
+
+// advance - течение времени
+func (c *fakeClock) advance(du time.Duration) {
+	c.wall = c.wall.Add(du)
+	c.boot += du
+}
+
+// jump - перевод настенных часов
+func (c *fakeClock) jump(du time.Duration) {
+	c.wall = c.wall.Add(du)
+}
+
+func newFakeClock(id string) *fakeClock {
+	return &fakeClock{
+		wall: time.Date(2021, 9, 22, 12, 0, 0, 0, time.UTC),
+		boot: time.Hour,
+		id:   id,
+	}
+}
+
+func newClockDelay(c Clock) *Delay {
+	// После Incr задержка 2s
+	return New(&Config{Max: 60, Factor: 2}).SetClock(c)
+}
+
+func TestClockJumpBackward(t *testing.T) {
+	for _, id := range []string{"", "boot-1"} {
+		t.Run("boot id "+id, func(t *testing.T) {
+
+			c := newFakeClock(id)
+			d := newClockDelay(c).Incr()
+
+			c.advance(2 * time.Second)
+			if !d.Allow() {
+				t.Fatal("Allow after the delay: got false")
+			}
+
+			c.jump(-time.Hour)
+
+			if d.Allow() {
+				t.Fatal("Allow right after an allowed attempt: got true")
+			}
+
+			if wait := d.Reserve().Delay(); wait != 2*time.Second {
+				t.Fatalf("Reserve after jump: got %s, want 2s", wait)
+			}
+
+			c.advance(4 * time.Second)
+			if !d.Allow() {
+				t.Fatal("Allow after the reservation: got false")
+			}
+		})
+	}
+}
+
+func TestClockJumpBackwardBeforeFailure(t *testing.T) {
+
+	// Без времени с загрузки время между последним обращением к задержке
+	// и переводом часов неизвестно, и ожидание начинается заново
+	tests := []struct {
+		id   string
+		want time.Duration
+	}{
+		{"", 2 * time.Second},
+		{"boot-1", time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run("boot id "+tt.id, func(t *testing.T) {
+
+			c := newFakeClock(tt.id)
+			d := newClockDelay(c).Incr()
+
+			c.advance(time.Second)
+			c.jump(-time.Hour)
+
+			if wait := d.Reserve().Delay(); wait != tt.want {
+				t.Fatalf("Reserve after jump: got %s, want %s", wait, tt.want)
+			}
+		})
+	}
+}
+
+func TestClockJumpForward(t *testing.T) {
+
+	c := newFakeClock("boot-1")
+	d := newClockDelay(c).Incr()
+
+	c.jump(time.Hour)
+
+	if d.Allow() {
+		t.Fatal("Allow after a forward jump: got true")
+	}
+
+	if wait := d.Reserve().Delay(); wait != 2*time.Second {
+		t.Fatalf("Reserve after jump: got %s, want 2s", wait)
+	}
+
+	// Без времени с загрузки перевод вперёд неотличим от течения времени
+	c = newFakeClock("")
+	d = newClockDelay(c).Incr()
+
+	c.jump(time.Hour)
+
+	if !d.Allow() {
+		t.Fatal("Allow after a forward jump without boot time: got false")
+	}
+
+	if wait := d.Reserve().Delay(); wait != 2*time.Second {
+		t.Fatalf("Reserve after Allow: got %s, want 2s", wait)
+	}
+}
+
+func TestAllowSpacing(t *testing.T) {
+
+	c := newFakeClock("boot-1")
+	d := newClockDelay(c).Incr()
+
+	c.advance(2 * time.Second)
+	if !d.Allow() {
+		t.Fatal("Allow after the delay: got false")
+	}
+
+	c.advance(time.Nanosecond)
+	if d.Allow() {
+		t.Fatal("second Allow 1ns later: got true")
+	}
+
+	c.advance(time.Nanosecond)
+	if wait := d.Reserve().Delay(); wait != 2*time.Second-2*time.Nanosecond {
+		t.Fatalf("Reserve after Allow: got %s, want 2s-2ns", wait)
+	}
+
+	c.advance(time.Nanosecond)
+	if wait := d.Reserve().Delay(); wait != 4*time.Second-3*time.Nanosecond {
+		t.Fatalf("second Reserve: got %s, want 4s-3ns", wait)
+	}
+
+	// Резервирования в прошлом не задерживают попытку
+	c.advance(time.Minute)
+	if !d.Allow() {
+		t.Fatal("Allow long after the reservations: got false")
+	}
+}
+
+func TestAllowSpacingSystemClock(t *testing.T) {
+
+	d := New(&Config{Max: 60, Factor: 2}).SetDurationUnits(50 * time.Millisecond).Incr()
+
+	time.Sleep(100 * time.Millisecond)
+
+	if !d.Allow() {
+		t.Fatal("Allow after the delay: got false")
+	}
+
+	if d.Allow() {
+		t.Fatal("second Allow: got true")
+	}
+
+	if wait := d.Reserve().Delay(); wait <= 0 {
+		t.Fatal("Reserve after Allow: no wait")
+	}
+}
+
+func TestReserveSpacing(t *testing.T) {
+
+	c := newFakeClock("boot-1")
+	d := newClockDelay(c).Incr()
+
+	for i, want := range []time.Duration{2 * time.Second, 4 * time.Second, 6 * time.Second} {
+		if wait := d.Reserve().Delay(); wait != want {
+			t.Fatalf("reservation %d: got %s, want %s", i, wait, want)
+		}
+	}
+
+	c.jump(-time.Hour)
+
+	r := d.Reserve()
+	if wait := r.Delay(); wait != 8*time.Second {
+		t.Fatalf("reservation after jump: got %s, want 8s", wait)
+	}
+
+	r.Cancel()
+
+	if wait := d.Reserve().Delay(); wait != 8*time.Second {
+		t.Fatalf("reservation after Cancel: got %s, want 8s", wait)
+	}
+}
+
+func TestRestore(t *testing.T) {
+
+	c := newFakeClock("boot-1")
+	s := newClockDelay(c).Incr().State()
+
+	tests := []struct {
+		name  string
+		clock func() *fakeClock
+		want  time.Duration
+	}{
+		{"same boot, wall jumped back", func() *fakeClock {
+			c := *c
+			c.advance(time.Second)
+			c.jump(-time.Hour)
+			return &c
+		}, time.Second},
+		{"same boot, wall jumped forward", func() *fakeClock {
+			c := *c
+			c.advance(time.Second)
+			c.jump(time.Hour)
+			return &c
+		}, time.Second},
+		{"after reboot", func() *fakeClock {
+			c := *c
+			c.wall = c.wall.Add(time.Second)
+			c.boot, c.id = time.Minute, "boot-2"
+			return &c
+		}, time.Second},
+		{"after reboot, wall behind failure", func() *fakeClock {
+			c := *c
+			c.wall = c.wall.Add(-time.Hour)
+			c.boot, c.id = time.Minute, "boot-2"
+			return &c
+		}, 2 * time.Second},
+		{"without boot time", func() *fakeClock {
+			c := *c
+			c.wall = c.wall.Add(time.Second)
+			c.id = ""
+			return &c
+		}, time.Second},
+		{"long ago", func() *fakeClock {
+			c := *c
+			c.advance(time.Hour)
+			return &c
+		}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			d := newClockDelay(tt.clock()).Restore(s)
+
+			if d.GetDelay() != 2 {
+				t.Fatalf("restored delay: got %d, want 2", d.GetDelay())
+			}
+
+			if wait := d.Reserve().Delay(); wait != tt.want {
+				t.Fatalf("Reserve: got %s, want %s", wait, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package exponentialbackoff
+
+import (
+	"sync"
+	"time"
+)
+
+// TimerWheel ...
+// Хешированное колесо таймеров: все ожидания, истекающие в один такт,
+// используют общий канал. Точность ограничена длительностью такта,
+// зато не создаётся таймер среды выполнения на каждое ожидание
+type TimerWheel struct {
+	tick  time.Duration
+	epoch time.Time
+
+	mu      sync.Mutex
+	slots   []map[int64]chan struct{} // слот -> абсолютный такт -> канал
+	current int64                     // последний обработанный такт
+	pending int                       // количество ожидающих каналов
+	running bool
+	stop    chan struct{}
+	shared  bool // общее колесо процесса, Stop не действует
+}
+
+var (
+	defaultWheelOnce sync.Once
+	defaultWheel     *TimerWheel
+)
+
+// NewTimerWheel ...
+// Возвращает колесо с длительностью такта tick и size слотами.
+// Горутина колеса работает, только пока есть ожидания
+func NewTimerWheel(tick time.Duration, size int) *TimerWheel {
+
+	if tick <= 0 {
+		tick = time.Millisecond
+	}
+
+	if size < 1 {
+		size = 1
+	}
+
+	w := &TimerWheel{
+		tick:  tick,
+		epoch: time.Now(),
+		slots: make([]map[int64]chan struct{}, size),
+		stop:  make(chan struct{}),
+	}
+
+	for i := range w.slots {
+		w.slots[i] = make(map[int64]chan struct{})
+	}
+
+	return w
+}
+
+// DefaultTimerWheel ...
+// Общее для процесса колесо с тактом 10ms. Его нельзя остановить
+func DefaultTimerWheel() *TimerWheel {
+
+	defaultWheelOnce.Do(func() {
+		defaultWheel = NewTimerWheel(10*time.Millisecond, 512)
+		defaultWheel.shared = true
+	})
+
+	return defaultWheel
+}
+
+// After ...
+// Возвращает канал, закрываемый не раньше, чем через du,
+// и не позже, чем через du плюс такт колеса.
+// После Stop возвращает закрытый канал
+func (w *TimerWheel) After(du time.Duration) <-chan struct{} {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	select {
+	case <-w.stop:
+		ch := make(chan struct{})
+		close(ch)
+		return ch
+	default:
+	}
+
+	elapsed := time.Since(w.epoch)
+
+	// Пока горутина колеса не работала, такты не обрабатывались:
+	// пропущенные такты пусты, и проходить их в advance не нужно
+	if !w.running {
+		w.current = int64(elapsed / w.tick)
+	}
+
+	target := int64((elapsed + du + w.tick - 1) / w.tick)
+	if target <= w.current {
+		target = w.current + 1
+	}
+
+	slot := w.slots[target%int64(len(w.slots))]
+
+	ch, ok := slot[target]
+	if !ok {
+		ch = make(chan struct{})
+		slot[target] = ch
+		w.pending++
+	}
+
+	if !w.running {
+		w.running = true
+		go w.run()
+	}
+
+	return ch
+}
+
+// Stop ...
+// Останавливает колесо и закрывает все ожидающие каналы.
+// Для DefaultTimerWheel ничего не делает
+func (w *TimerWheel) Stop() {
+
+	if w.shared {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	select {
+	case <-w.stop:
+		return
+	default:
+		close(w.stop)
+	}
+
+	for _, slot := range w.slots {
+		for tick, ch := range slot {
+			close(ch)
+			delete(slot, tick)
+		}
+	}
+
+	w.pending = 0
+}
+
+func (w *TimerWheel) run() {
+
+	t := time.NewTicker(w.tick)
+	defer t.Stop()
+
+	for {
+
+		select {
+		case <-w.stop:
+			return
+		case <-t.C:
+		}
+
+		if !w.advance(int64(time.Since(w.epoch) / w.tick)) {
+			return
+		}
+	}
+}
+
+// advance обрабатывает такты до now включительно.
+// Возвращает false, если ожиданий не осталось и горутина должна завершиться
+func (w *TimerWheel) advance(now int64) bool {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for ; w.current < now && w.pending > 0; w.current++ {
+
+		tick := w.current + 1
+		slot := w.slots[tick%int64(len(w.slots))]
+
+		if ch, ok := slot[tick]; ok {
+			close(ch)
+			delete(slot, tick)
+			w.pending--
+		}
+	}
+
+	if w.current < now {
+		w.current = now
+	}
+
+	if w.pending == 0 {
+		w.running = false
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,130 @@
+package exponentialbackoff
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimerWheelAfter(t *testing.T) {
+
+	w := NewTimerWheel(time.Millisecond, 16)
+	defer w.Stop()
+
+	for _, du := range []time.Duration{0, time.Millisecond, 5 * time.Millisecond, 40 * time.Millisecond} {
+
+		start := time.Now()
+		<-w.After(du)
+
+		if elapsed := time.Since(start); elapsed < du {
+			t.Errorf("After(%s) fired after %s", du, elapsed)
+		}
+	}
+}
+
+func TestTimerWheelIdle(t *testing.T) {
+
+	w := NewTimerWheel(time.Millisecond, 16)
+	defer w.Stop()
+
+	<-w.After(time.Millisecond)
+
+	// Дождаться остановки горутины колеса и пропустить такты
+	time.Sleep(50 * time.Millisecond)
+
+	w.After(time.Millisecond)
+
+	w.mu.Lock()
+	current := w.current
+	w.mu.Unlock()
+
+	if stale := int64(time.Since(w.epoch)/w.tick) - current; stale > 5 {
+		t.Fatalf("%d ticks left to walk after an idle period", stale)
+	}
+}
+
+func TestTimerWheelStop(t *testing.T) {
+
+	w := NewTimerWheel(time.Millisecond, 16)
+	ch := w.After(time.Hour)
+
+	w.Stop()
+
+	select {
+	case <-ch:
+	default:
+		t.Fatal("pending channel not closed by Stop")
+	}
+
+	select {
+	case <-w.After(time.Hour):
+	default:
+		t.Fatal("After returned an open channel after Stop")
+	}
+}
+
+func TestDefaultTimerWheelStop(t *testing.T) {
+
+	w := DefaultTimerWheel()
+	w.Stop()
+
+	select {
+	case <-w.After(time.Hour):
+		t.Fatal("Stop stopped the shared wheel")
+	default:
+	}
+}
+
+// Сравнение с отдельным таймером на каждое ожидание: все ожидания
+// регистрируются сразу и ожидаются одной горутиной, поэтому B/op и
+// ns/op относятся к самим таймерам, а не к горутинам ожидающих
+const benchSleep = 20 * time.Millisecond
+
+func BenchmarkTimerWheel(b *testing.B) {
+	for _, n := range []int{1e5, 1e6} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+
+			w := NewTimerWheel(time.Millisecond, 512)
+			defer w.Stop()
+
+			chans := make([]<-chan struct{}, n)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+
+				for j := range chans {
+					chans[j] = w.After(benchSleep)
+				}
+
+				for _, ch := range chans {
+					<-ch
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkTimerPerCall(b *testing.B) {
+	for _, n := range []int{1e5, 1e6} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+
+			timers := make([]*time.Timer, n)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+
+				for j := range timers {
+					timers[j] = time.NewTimer(benchSleep)
+				}
+
+				for _, t := range timers {
+					<-t.C
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package exponentialbackoff
+
+import (
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Переменная окружения с коэффициентом масштабирования времени задержек
+const TimeScaleEnv = "EXPONENTIALBACKOFF_TIME_SCALE"
+
+// Коэффициент масштабирования в виде битов float64, 0 - не задан
+var timeScale uint64
+
+func init() {
+
+	v, ok := os.LookupEnv(TimeScaleEnv)
+	if !ok || v == "" {
+		return
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Printf("exponentialbackoff: ignoring %s=%q: %v", TimeScaleEnv, v, err)
+		return
+	}
+
+	SetTimeScale(f)
+}
+
+// SetTimeScale ...
+// Установить для всего процесса коэффициент, на который умножаются
+// все задержки. Предназначено для тестов и тестовых стендов.
+// 1 отключает масштабирование, значения <= 0 игнорируются
+func SetTimeScale(f float64) {
+
+	if f <= 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		log.Printf("exponentialbackoff: ignoring invalid time scale %v", f)
+		return
+	}
+
+	if f == 1 {
+		atomic.StoreUint64(&timeScale, 0)
+		return
+	}
+
+	atomic.StoreUint64(&timeScale, math.Float64bits(f))
+	log.Printf("exponentialbackoff: time scale %v is active, all delays are multiplied by it", f)
+}
+
+// TimeScale ...
+// Текущий коэффициент масштабирования времени задержек
+func TimeScale() float64 {
+
+	bits := atomic.LoadUint64(&timeScale)
+	if bits == 0 {
+		return 1
+	}
+
+	return math.Float64frombits(bits)
+}
+
+func scaleDuration(du time.Duration) time.Duration {
+
+	bits := atomic.LoadUint64(&timeScale)
+	if bits == 0 {
+		return du
+	}
+
+	return time.Duration(float64(du) * math.Float64frombits(bits))
+}
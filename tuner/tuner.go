@@ -0,0 +1,257 @@
+// Online tuning of backoff parameters
+// Подстройка параметров задержки по наблюдаемым результатам попыток
+//
+// Попытки, выполненные после Backoff, группируются по времени ожидания
+// (интервалы с удвоением длительности). Для каждой группы оценивается
+// вероятность успеха p и ожидаемое время до успеха (w + c) / p, где w -
+// ожидание, c - стоимость попытки. По группе с наименьшим ожидаемым временем
+// выбирается целевое ожидание w*, после чего единица времени задержки
+// приближается к w* / (2 * factor), а максимум - к 4 * w*.
+
+package tuner
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+const (
+	minBucket   = time.Millisecond // граница первой группы
+	bucketCount = 32
+	keepLast    = 100 // количество хранимых решений
+)
+
+type Config struct {
+	MinUnit     time.Duration // Границы единицы времени задержки
+	MaxUnit     time.Duration
+	MinMax      int // Границы максимального значения задержки
+	MaxMax      int
+	AttemptCost time.Duration // Стоимость одной попытки
+	Window      int           // Количество наблюдений между подстройками
+	MinSamples  int           // Минимум наблюдений в группе для её учёта
+}
+
+// Decision ...
+// Решение, принятое при подстройке
+type Decision struct {
+	Time     time.Time
+	Samples  int           // Наблюдений в выбранной группе
+	Success  float64       // Оценка вероятности успеха в выбранной группе
+	Wait     time.Duration // Целевое ожидание w*
+	Expected time.Duration // Ожидаемое время до успеха при w*
+	OldUnit  time.Duration
+	NewUnit  time.Duration
+	OldMax   int
+	NewMax   int
+}
+
+type bucket struct {
+	attempts  int
+	successes int
+}
+
+type Tuner struct {
+	delay *exponentialbackoff.Delay
+	cfg   Config
+
+	mu        sync.Mutex
+	buckets   [bucketCount]bucket
+	observed  int
+	decisions []Decision
+}
+
+// New ...
+// Возвращает объект подстройки задержки d
+func New(d *exponentialbackoff.Delay, c Config) *Tuner {
+
+	if c.MinUnit <= 0 {
+		c.MinUnit = time.Millisecond
+	}
+
+	if c.MaxUnit < c.MinUnit {
+		c.MaxUnit = c.MinUnit
+	}
+
+	if c.MinMax < 1 {
+		c.MinMax = 1
+	}
+
+	if c.MaxMax < c.MinMax {
+		c.MaxMax = c.MinMax
+	}
+
+	if c.Window < 1 {
+		c.Window = 50
+	}
+
+	if c.MinSamples < 1 {
+		c.MinSamples = 5
+	}
+
+	return &Tuner{
+		delay: d,
+		cfg:   c,
+	}
+}
+
+// Observe ...
+// Учитывает результат попытки, выполненной после ожидания waited.
+// Попытки без ожидания не учитываются
+func (t *Tuner) Observe(waited time.Duration, success bool) {
+
+	if waited <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := &t.buckets[bucketOf(waited)]
+	b.attempts++
+	if success {
+		b.successes++
+	}
+
+	t.observed++
+	if t.observed%t.cfg.Window == 0 {
+		t.tune()
+		t.forget()
+	}
+}
+
+// Decisions ...
+// Последние решения подстройки, от старых к новым
+func (t *Tuner) Decisions() []Decision {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Decision(nil), t.decisions...)
+}
+
+// tune выбирает целевое ожидание и подстраивает задержку.
+// Вызывается под блокировкой
+func (t *Tuner) tune() {
+
+	best := -1
+	var bestExpected float64
+
+	for i, b := range t.buckets {
+
+		if b.attempts < t.cfg.MinSamples {
+			continue
+		}
+
+		// Сглаживание Лапласа: группа без успехов не даёт бесконечности
+		p := float64(b.successes+1) / float64(b.attempts+2)
+		expected := (float64(bucketWait(i)) + float64(t.cfg.AttemptCost)) / p
+
+		if best < 0 || expected < bestExpected {
+			best, bestExpected = i, expected
+		}
+	}
+
+	if best < 0 {
+		return
+	}
+
+	b := t.buckets[best]
+	wait := bucketWait(best)
+	factor := t.delay.GetFactor()
+
+	d := Decision{
+		Time:     time.Now(),
+		Samples:  b.attempts,
+		Success:  float64(b.successes+1) / float64(b.attempts+2),
+		Wait:     wait,
+		Expected: time.Duration(bestExpected),
+		OldUnit:  t.delay.GetDurationUnits(),
+		OldMax:   t.delay.GetMax(),
+	}
+
+	// Изменения делаются на полпути к цели в логарифмическом масштабе,
+	// чтобы одиночный выброс не менял задержку резко
+	target := wait / time.Duration(2*factor)
+	d.NewUnit = clampDuration(geomMean(d.OldUnit, target), t.cfg.MinUnit, t.cfg.MaxUnit)
+
+	targetMax := int(math.Ceil(float64(4*wait) / float64(d.NewUnit)))
+	d.NewMax = clampInt(int(math.Round(math.Sqrt(float64(d.OldMax)*float64(targetMax)))), t.cfg.MinMax, t.cfg.MaxMax)
+
+	t.delay.SetDurationUnits(d.NewUnit)
+	t.delay.SetMax(d.NewMax)
+
+	t.decisions = append(t.decisions, d)
+	if len(t.decisions) > keepLast {
+		t.decisions = t.decisions[len(t.decisions)-keepLast:]
+	}
+}
+
+// forget вдвое уменьшает счётчики групп, чтобы старые
+// наблюдения постепенно теряли вес
+func (t *Tuner) forget() {
+	for i := range t.buckets {
+		t.buckets[i].attempts /= 2
+		t.buckets[i].successes /= 2
+	}
+}
+
+// bucketOf возвращает номер группы: [0, 1ms), [1ms, 2ms), [2ms, 4ms), ...
+func bucketOf(waited time.Duration) int {
+
+	i := 0
+	for limit := minBucket; waited >= limit && i < bucketCount-1; limit *= 2 {
+		i++
+	}
+
+	return i
+}
+
+// bucketWait возвращает характерное ожидание группы (середину интервала)
+func bucketWait(i int) time.Duration {
+
+	if i == 0 {
+		return minBucket / 2
+	}
+
+	lo := minBucket << uint(i-1)
+
+	return lo + lo/2
+}
+
+func geomMean(a, b time.Duration) time.Duration {
+
+	if a <= 0 || b <= 0 {
+		return b
+	}
+
+	return time.Duration(math.Sqrt(float64(a) * float64(b)))
+}
+
+func clampDuration(v, lo, hi time.Duration) time.Duration {
+
+	if v < lo {
+		return lo
+	}
+
+	if v > hi {
+		return hi
+	}
+
+	return v
+}
+
+func clampInt(v, lo, hi int) int {
+
+	if v < lo {
+		return lo
+	}
+
+	if v > hi {
+		return hi
+	}
+
+	return v
+}
@@ -0,0 +1,141 @@
+package tuner
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	exponentialbackoff "gitlab.alx/rb/exponentialbackoff/v1"
+)
+
+// recovery - синтетическая зависимость: вероятность успеха попытки
+// растёт линейно с ожиданием и достигает 1 через 200ms
+func recovery(wait time.Duration) float64 {
+
+	p := float64(wait) / float64(200*time.Millisecond)
+	if p > 1 {
+		return 1
+	}
+
+	return p
+}
+
+// simulate подаёт rounds окон наблюдений, равномерно распределённых по группам
+// ожиданий от 3ms до 1.5s, с долей успехов, заданной кривой recovery
+func simulate(tn *Tuner, rounds int) {
+
+	credit := make(map[int]float64)
+
+	for r := 0; r < rounds*tn.cfg.Window; r++ {
+
+		i := 3 + r%10
+		wait := bucketWait(i)
+
+		credit[i] += recovery(wait)
+		success := credit[i] >= 1
+		if success {
+			credit[i]--
+		}
+
+		tn.Observe(wait, success)
+	}
+}
+
+func newDelay() *exponentialbackoff.Delay {
+	return exponentialbackoff.New(&exponentialbackoff.Config{Max: 60, Factor: 2})
+}
+
+func TestConverges(t *testing.T) {
+
+	d := newDelay()
+
+	tn := New(d, Config{
+		MinUnit:     time.Millisecond,
+		MaxUnit:     10 * time.Second,
+		MinMax:      1,
+		MaxMax:      1000,
+		AttemptCost: 50 * time.Millisecond,
+		Window:      100,
+	})
+
+	simulate(tn, 30)
+
+	decisions := tn.Decisions()
+	if len(decisions) == 0 {
+		t.Fatal("no decisions")
+	}
+
+	last := decisions[len(decisions)-1]
+
+	// (w + 50ms) / p(w) минимально в группе [128ms, 256ms)
+	if want := bucketWait(8); last.Wait != want {
+		t.Fatalf("chosen wait: got %s, want %s", last.Wait, want)
+	}
+
+	wantUnit := bucketWait(8) / 4
+	if u := d.GetDurationUnits(); u < wantUnit*9/10 || u > wantUnit*11/10 {
+		t.Errorf("unit: got %s, want about %s", u, wantUnit)
+	}
+
+	if m := d.GetMax(); m < 15 || m > 17 {
+		t.Errorf("max: got %d, want about 16", m)
+	}
+
+	// Первое ожидание после Incr - factor единиц, около w* / 2
+	if du := d.Incr().Duration(); du < bucketWait(8)/3 || du > bucketWait(8) {
+		t.Errorf("first wait: got %s", du)
+	}
+}
+
+func TestBounds(t *testing.T) {
+
+	d := newDelay()
+
+	tn := New(d, Config{
+		MinUnit:     100 * time.Millisecond,
+		MaxUnit:     time.Second,
+		MinMax:      2,
+		MaxMax:      5,
+		AttemptCost: 50 * time.Millisecond,
+		Window:      100,
+	})
+
+	simulate(tn, 30)
+
+	if u := d.GetDurationUnits(); u != 100*time.Millisecond {
+		t.Errorf("unit: got %s, want MinUnit 100ms", u)
+	}
+
+	if m := d.GetMax(); m != 5 {
+		t.Errorf("max: got %d, want MaxMax 5", m)
+	}
+
+	for _, dec := range tn.Decisions() {
+		if dec.NewUnit < 100*time.Millisecond || dec.NewUnit > time.Second || dec.NewMax < 2 || dec.NewMax > 5 {
+			t.Fatalf("decision out of bounds: %+v", dec)
+		}
+	}
+}
+
+func TestConcurrentObserve(t *testing.T) {
+
+	d := newDelay().SetDurationUnits(time.Microsecond)
+	tn := New(d, Config{Window: 10, MinSamples: 1})
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				d.Incr()
+				_, _, waited := d.Backoff(context.Background())
+				tn.Observe(waited, (i+g)%3 == 0)
+			}
+		}(g)
+	}
+
+	wg.Wait()
+}